@@ -76,6 +76,8 @@ and listing available agents.`,
 		Before: initializeLogging,
 		Commands: []*cli.Command{
 			internalcli.NewApplyCommand(),
+			internalcli.NewCleanCommand(),
+			internalcli.NewDiffCommand(),
 			internalcli.NewInitCommand(),
 		},
 		Metadata: map[string]interface{}{
@@ -88,8 +90,8 @@ and listing available agents.`,
 
 	// Run the application
 	if err := rootCmd.Run(context.Background(), os.Args); err != nil {
-		// Error handling should be done by ExitErrHandler
-		os.Exit(1)
+		// Error output is handled by ExitErrHandler; typed errors select the exit status
+		os.Exit(util.ExitCodeOf(err))
 	}
 }
 
@@ -165,6 +167,8 @@ func initializeLogging(ctx context.Context, cmd *cli.Command) (context.Context,
 		fmt.Fprintf(os.Stderr, "Error initializing logger: %v\n", err)
 		os.Exit(1)
 	}
+	// Warnings of the agent formatters go through the global logger
+	log.SetGlobalLogger(logger)
 
 	// Set up deferred logger sync via cmd's After hook
 	registerLoggerCleanup(cmd, logger, logConfig.Level)
@@ -245,5 +249,5 @@ func handleExitError(ctx context.Context, cmd *cli.Command, err error) {
 		}
 	}
 
-	// Exit code is handled by the app.Run caller
+	// Exit code is handled by the app.Run caller, using util.ExitCodeOf
 }
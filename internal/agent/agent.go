@@ -16,8 +16,14 @@ type Agent interface {
 	// FormatMCP formats an MCP command for this agent
 	FormatMCP(agent, command string, args ...string) string
 
-	// FormatMemory processes a memory context for this agent
-	FormatMemory(content string) (string, error)
+	// FormatCommandRef formats how a command generated for this agent is invoked
+	FormatCommandRef(command model.Command) (string, error)
+
+	// FormatModeRef formats a reference to a mode generated for this agent, as used to switch or delegate to it
+	FormatModeRef(mode model.Mode) (string, error)
+
+	// FormatMemory processes memory contexts for this agent
+	FormatMemory(memories []model.Memory) (string, error)
 
 	// FormatCommand processes a command definition for this agent
 	FormatCommand(commands []model.Command) (string, error)
@@ -34,3 +40,91 @@ type Agent interface {
 	// ModePath returns the default path for mode files based on user scope
 	ModePath(userScope bool) string
 }
+
+// FileNamer is an optional interface for agents whose per-file outputs use a
+// naming scheme other than the input file's basename (e.g. a different extension).
+type FileNamer interface {
+	// FileName returns the output file name for an input file of the given task type
+	FileName(taskType string, inputPath string) string
+}
+
+// ToolFormatter is an optional interface for agents with named tools, so that templates
+// can refer to the agent's tools by their neutral name (see ToolRead and the other tools).
+type ToolFormatter interface {
+	// FormatToolRef formats a reference to the agent's tools for a neutral tool
+	FormatToolRef(tool string) (string, error)
+}
+
+// MemoryFileFormatter is an optional interface for agents whose per-file memory
+// outputs (directory mode) differ from a single concatenated memory file.
+type MemoryFileFormatter interface {
+	// FormatMemoryFile processes a memory context written to its own file
+	FormatMemoryFile(memory model.Memory) (string, error)
+}
+
+// MemoryLocator is an optional interface for agents that read memory files from
+// subdirectories, allowing a memory source to choose where its output is placed.
+type MemoryLocator interface {
+	// MemoryDir returns the subdirectory, relative to the output directory, for a
+	// memory source with the given frontmatter. An empty string means the output root.
+	MemoryDir(frontmatter map[string]any) (string, error)
+}
+
+// SkillFormatter is an optional interface for agents that support skills: directories
+// holding a SKILL.md entry file together with supporting scripts and resources.
+type SkillFormatter interface {
+	// FormatSkill renders the SKILL.md entry file of a skill for this agent
+	FormatSkill(skill model.Skill) (string, error)
+
+	// SkillPath returns the default directory for skills based on user scope
+	SkillPath(userScope bool) string
+}
+
+// MCPConfigurator is an optional interface for agents whose MCP server configuration
+// file can be generated. It is unrelated to FormatMCP, which formats tool references.
+type MCPConfigurator interface {
+	// FormatMCPConfig renders the agent's MCP configuration file for the given servers
+	FormatMCPConfig(servers []model.MCPServer) (string, error)
+
+	// MCPConfigPath returns the default path of the MCP configuration file based on user scope.
+	// An empty string means the agent has no default location for the scope.
+	MCPConfigPath(userScope bool) string
+}
+
+// SharedMCPConfigurator is an optional interface for MCP configurators whose default
+// configuration file also holds other settings, so it is always merged instead of overwritten.
+type SharedMCPConfigurator interface {
+	// MCPConfigShared reports whether the default MCP configuration file of the scope
+	// holds settings agent-sync does not generate
+	MCPConfigShared(userScope bool) bool
+}
+
+// IgnoreFormatter is an optional interface for agents that can be told which paths
+// they must not read, e.g. through an ignore file or deny rules in a settings file.
+type IgnoreFormatter interface {
+	// FormatIgnore renders the agent's ignore mechanism for gitignore-style patterns
+	FormatIgnore(patterns []string) (string, error)
+
+	// IgnorePath returns the default path of the ignore file based on user scope.
+	// An empty string means the agent has no default location for the scope.
+	IgnorePath(userScope bool) string
+}
+
+// SettingsFormatter is an optional interface for agents with a JSON settings file
+// (permissions, hooks, environment, model, ...) that can be generated.
+type SettingsFormatter interface {
+	// FormatSettings renders the agent's settings file from a settings document
+	FormatSettings(settings map[string]any) (string, error)
+
+	// SettingsPath returns the default path of the settings file based on user scope
+	SettingsPath(userScope bool) string
+}
+
+// MergeKeyer is an optional interface for agents whose aggregated structured outputs
+// (YAML or JSON) hold a list of entries that may be shared with other tools.
+// Merging replaces only the entries agent-sync generates, matched by their key.
+type MergeKeyer interface {
+	// MergeKeys returns the top-level list key and the key identifying an entry for
+	// outputs of the given task type. ok is false if the output is not a keyed list.
+	MergeKeys(taskType string) (listKey string, idKey string, ok bool)
+}
@@ -0,0 +1,41 @@
+package agent
+
+import "github.com/uphy/agent-sync/internal/model"
+
+// AmazonQ implements the Amazon Q Developer-specific conversion logic
+type AmazonQ struct {
+	plainMarkdown
+}
+
+// ID returns the unique identifier for Amazon Q agent
+func (a *AmazonQ) ID() string {
+	return "amazonq"
+}
+
+// Name returns the display name for Amazon Q agent
+func (a *AmazonQ) Name() string {
+	return "Amazon Q"
+}
+
+// FormatCommand joins the command bodies into an Amazon Q saved prompt
+func (a *AmazonQ) FormatCommand(commands []model.Command) (string, error) {
+	return formatPlainCommands(a.ID(), commands), nil
+}
+
+// FormatCommandRef formats an Amazon Q saved prompt invocation, which uses an @-mention
+func (a *AmazonQ) FormatCommandRef(command model.Command) (string, error) {
+	return "@" + SourceName(command.Path), nil
+}
+
+// MemoryPath returns the default path for Amazon Q agent memory (rule) files
+func (a *AmazonQ) MemoryPath(userScope bool) string {
+	return ".amazonq/rules/"
+}
+
+// CommandPath returns the default path for Amazon Q agent command (saved prompt) files
+func (a *AmazonQ) CommandPath(userScope bool) string {
+	if userScope {
+		return ".aws/amazonq/prompts/"
+	}
+	return ".amazonq/prompts/"
+}
@@ -2,11 +2,14 @@ package agent
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/log"
 	"github.com/uphy/agent-sync/internal/model"
+	"go.uber.org/zap"
 )
 
 // Claude implements the Claude-specific conversion logic
@@ -32,12 +35,29 @@ func (c *Claude) FormatMCP(agent, command string, args ...string) string {
 	return formatMCP(agent, command, args...)
 }
 
-// FormatMemory processes a memory context for Claude agent
-func (c *Claude) FormatMemory(content string) (string, error) {
+// FormatCommandRef formats a Claude slash command invocation
+func (c *Claude) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef formats an @-mention of a Claude subagent
+func (c *Claude) FormatModeRef(mode model.Mode) (string, error) {
+	var sa ClaudeSubagent
+	if err := unmarshalSection(mode.Raw, "claude", &sa); err != nil {
+		return "", fmt.Errorf("claude frontmatter parse error: %w", err)
+	}
+	if sa.Name == "" {
+		return "", fmt.Errorf("claude subagent requires claude.name")
+	}
+	return "@agent-" + sa.Name, nil
+}
+
+// FormatMemory processes memory contexts for Claude agent
+func (c *Claude) FormatMemory(memories []model.Memory) (string, error) {
 	// Process the memory context for Claude
 	// For now, we're simply returning the content as is
 	// Any Claude-specific formatting rules could be applied here
-	return content, nil
+	return joinMemories(memories), nil
 }
 
 // FormatCommand processes command definitions for Claude agent
@@ -61,6 +81,15 @@ func (c *Claude) FormatCommand(commands []model.Command) (string, error) {
 		if fm.Description == "" && cmd.Description != "" {
 			fm.Description = cmd.Description
 		}
+		// Fallback to the neutral tools when claude.allowed-tools is not provided
+		if fm.AllowedTools == "" {
+			spec, err := parseToolSpec(cmd.Path, cmd.Raw)
+			if err != nil {
+				return "", fmt.Errorf("claude command %s: %w", cmd.Path, err)
+			}
+			allowed, _ := claudeTools(cmd.Path, spec, true)
+			fm.AllowedTools = strings.Join(allowed, ", ")
+		}
 
 		// Include frontmatter if Claude-specific attributes exist
 		if fm.Description != "" || fm.AllowedTools != "" {
@@ -93,6 +122,159 @@ func (c *Claude) CommandPath(userScope bool) string {
 	return ".claude/commands/"
 }
 
+// ClaudeSkill is the frontmatter of a Claude SKILL.md file
+type ClaudeSkill struct {
+	Name         string `yaml:"name"`
+	Description  string `yaml:"description"`
+	AllowedTools any    `yaml:"allowed-tools,omitempty"`
+	Model        string `yaml:"model,omitempty"`
+}
+
+// FormatSkill renders the SKILL.md entry file of a Claude skill.
+// name and description come from the top-level frontmatter and may be overridden
+// in the 'claude' section, which also provides allowed-tools and model.
+func (c *Claude) FormatSkill(skill model.Skill) (string, error) {
+	var cs ClaudeSkill
+	if err := unmarshalSection(skill.Raw, "claude", &cs); err != nil {
+		return "", fmt.Errorf("claude frontmatter parse error: %w", err)
+	}
+	if cs.Name == "" {
+		cs.Name = skill.Name
+	}
+	if cs.Description == "" {
+		cs.Description = skill.Description
+	}
+	if cs.Description == "" {
+		return "", fmt.Errorf("claude skill %q requires a description", cs.Name)
+	}
+	if _, err := joinList("claude.allowed-tools", cs.AllowedTools); err != nil {
+		return "", err
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(cs)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claude skill frontmatter: %w", err)
+	}
+	return yamlWithFences + strings.TrimLeft(skill.Content, "\n"), nil
+}
+
+// SkillPath returns the default path for Claude agent skill directories
+func (c *Claude) SkillPath(userScope bool) string {
+	return ".claude/skills/"
+}
+
+// FormatMCPConfig renders the Claude MCP configuration (.mcp.json / ~/.claude.json)
+func (c *Claude) FormatMCPConfig(servers []model.MCPServer) (string, error) {
+	return renderMCPConfig(c.ID(), "mcpServers", servers, mcpEntryOptions{
+		types: map[string]string{
+			model.MCPTypeStdio: "stdio",
+			model.MCPTypeHTTP:  "http",
+			model.MCPTypeSSE:   "sse",
+		},
+	})
+}
+
+// MCPConfigPath returns the default path for the Claude MCP configuration.
+// User-scoped servers live in ~/.claude.json, which also holds other settings.
+func (c *Claude) MCPConfigPath(userScope bool) string {
+	if userScope {
+		return ".claude.json"
+	}
+	return ".mcp.json"
+}
+
+// MCPConfigShared reports whether the Claude MCP configuration holds other settings,
+// which is the case for ~/.claude.json
+func (c *Claude) MCPConfigShared(userScope bool) bool {
+	return userScope
+}
+
+// FormatIgnore renders ignore patterns as Read deny rules in Claude settings.json.
+// Negated patterns cannot be expressed as deny rules and are skipped with a warning.
+func (c *Claude) FormatIgnore(patterns []string) (string, error) {
+	deny := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			log.Warn("Negated ignore pattern cannot be expressed as a Claude deny rule and is skipped",
+				zap.String("pattern", pattern))
+			continue
+		}
+		// Directory patterns deny everything below the directory
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+		// Anchored patterns are relative to the project root
+		if strings.HasPrefix(pattern, "/") {
+			pattern = "." + pattern
+		}
+		deny = append(deny, "Read("+pattern+")")
+	}
+	return marshalJSON(map[string]any{
+		"permissions": map[string]any{"deny": deny},
+	})
+}
+
+// IgnorePath returns the default path for Claude ignore rules (settings.json)
+func (c *Claude) IgnorePath(userScope bool) string {
+	return ".claude/settings.json"
+}
+
+// claudePermissionLists are the rule lists of the Claude settings 'permissions' object
+var claudePermissionLists = []string{"allow", "deny", "ask"}
+
+// FormatSettings renders Claude settings.json.
+// Permission rule lists are checked to contain only strings.
+func (c *Claude) FormatSettings(settings map[string]any) (string, error) {
+	if permissions, ok := settings["permissions"]; ok {
+		pm, ok := permissions.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("claude settings: permissions must be a mapping, got %T", permissions)
+		}
+		for _, key := range claudePermissionLists {
+			if _, err := joinList("permissions."+key, pm[key]); err != nil {
+				return "", fmt.Errorf("claude settings: %w", err)
+			}
+			if _, isString := pm[key].(string); isString {
+				return "", fmt.Errorf("claude settings: permissions.%s must be a list of rules", key)
+			}
+		}
+	}
+	return marshalJSON(settings)
+}
+
+// SettingsPath returns the default path for Claude settings
+func (c *Claude) SettingsPath(userScope bool) string {
+	return ".claude/settings.json"
+}
+
+// claudeSubagentKeys lists the subagent frontmatter keys understood by Claude Code,
+// in the order they are written to the generated file.
+var claudeSubagentKeys = []string{
+	"name",
+	"description",
+	"tools",
+	"disallowedTools",
+	"model",
+	"permissionMode",
+	"skills",
+	"hooks",
+	"color",
+}
+
+// ClaudeSubagent is the typed view of the 'claude' section of a mode source.
+// It is used to validate the known keys before they are written out.
+type ClaudeSubagent struct {
+	Name            string         `yaml:"name"`
+	Description     string         `yaml:"description"`
+	Tools           any            `yaml:"tools"`
+	DisallowedTools any            `yaml:"disallowedTools"`
+	Model           string         `yaml:"model"`
+	PermissionMode  string         `yaml:"permissionMode"`
+	Skills          any            `yaml:"skills"`
+	Hooks           map[string]any `yaml:"hooks"`
+	Color           string         `yaml:"color"`
+}
+
 // FormatMode processes mode definitions for Claude agent
 // Claude does not support combining multiple modes.
 func (c *Claude) FormatMode(modes []model.Mode) (string, error) {
@@ -107,71 +289,166 @@ func (c *Claude) FormatMode(modes []model.Mode) (string, error) {
 
 	mode := modes[0]
 
-	// Define ClaudeMode; tools are plain []string (no special normalization)
-	type ClaudeMode struct {
-		Name        string   `yaml:"name"`
-		Description string   `yaml:"description"`
-		Tools       []string `yaml:"tools,omitempty"`
+	var section map[string]any
+	if err := unmarshalSection(mode.Raw, "claude", &section); err != nil {
+		return "", fmt.Errorf("claude frontmatter parse error: %w", err)
 	}
-
-	var cm ClaudeMode
-	// Unmarshal section if present
-	if mode.Raw != nil {
-		_ = mode.UnmarshalSection("claude", &cm)
+	if section == nil {
+		section = map[string]any{}
 	}
 
+	var sa ClaudeSubagent
+	if err := unmarshalSection(mode.Raw, "claude", &sa); err != nil {
+		return "", fmt.Errorf("claude frontmatter parse error: %w", err)
+	}
 	// Fallback to common description when empty
-	if cm.Description == "" && mode.Description != "" {
-		cm.Description = mode.Description
-	}
-
-	// Encode with goccy/go-yaml, then post-process to match expected quoting style
-	var sb strings.Builder
-	enc := yaml.NewEncoder(
-		&sb,
-		yaml.Indent(2),
-		yaml.UseSingleQuote(false),
-		yaml.IndentSequence(true),
-		yaml.UseLiteralStyleIfMultiline(true),
-	)
-	if err := enc.Encode(cm); err != nil {
+	if sa.Description == "" && mode.Description != "" {
+		section["description"] = mode.Description
+		sa.Description = mode.Description
+	}
+	if sa.Name == "" {
+		return "", fmt.Errorf("claude subagent requires claude.name")
+	}
+	if sa.Description == "" {
+		return "", fmt.Errorf("claude subagent %q requires a description", sa.Name)
+	}
+	// Fallback to the neutral tools for tool lists not provided under claude
+	if sa.Tools == nil || sa.DisallowedTools == nil {
+		spec, err := parseToolSpec(mode.Path, mode.Raw)
+		if err != nil {
+			return "", fmt.Errorf("claude subagent %q: %w", sa.Name, err)
+		}
+		tools, disallowed := claudeTools(mode.Path, spec, false)
+		if sa.Tools == nil && len(tools) > 0 {
+			section["tools"] = strings.Join(tools, ", ")
+		}
+		if sa.DisallowedTools == nil && len(disallowed) > 0 {
+			section["disallowedTools"] = strings.Join(disallowed, ", ")
+		}
+	}
+	for _, field := range []struct {
+		key   string
+		value any
+	}{
+		{"claude.tools", sa.Tools},
+		{"claude.disallowedTools", sa.DisallowedTools},
+		{"claude.skills", sa.Skills},
+	} {
+		if _, err := joinList(field.key, field.value); err != nil {
+			return "", err
+		}
+	}
+
+	// Known keys keep a stable order; unknown keys follow in sorted order
+	fm := yaml.MapSlice{}
+	known := make(map[string]bool, len(claudeSubagentKeys))
+	for _, key := range claudeSubagentKeys {
+		known[key] = true
+		if v, ok := section[key]; ok && v != nil {
+			fm = append(fm, yaml.MapItem{Key: key, Value: v})
+		}
+	}
+	var unknown []string
+	for key := range section {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		log.Warn("Unknown Claude subagent frontmatter key is passed through as-is",
+			zap.String("subagent", sa.Name), zap.String("key", key))
+		fm = append(fm, yaml.MapItem{Key: key, Value: section[key]})
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(fm)
+	if err != nil {
 		return "", fmt.Errorf("failed to marshal claude mode frontmatter: %w", err)
 	}
-	yamlOut := strings.TrimRight(sb.String(), "\n") // avoid trailing blank line before closing ---
-
-	// Tests expect quoted scalars and quoted list items. goccy/yaml encoder
-	// doesn't force quotes for plain scalars, so patch them deterministically.
-	// Only touch the specific fields we emit: name, description, tools items.
-	var out strings.Builder
-	lines := strings.Split(yamlOut, "\n")
-	for i, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "name: "):
-			val := strings.TrimSpace(strings.TrimPrefix(line, "name: "))
-			if val != "" && !strings.HasPrefix(val, "\"") && !strings.HasSuffix(val, "\"") {
-				line = "name: " + fmt.Sprintf("%q", val)
+	return yamlWithFences + mode.Content, nil
+}
+
+// claudeToolNames maps neutral tools to Claude Code tools
+var claudeToolNames = map[string][]string{
+	ToolRead:    {"Read"},
+	ToolSearch:  {"Grep", "Glob"},
+	ToolEdit:    {"Edit", "Write"},
+	ToolCommand: {"Bash"},
+	ToolWeb:     {"WebFetch", "WebSearch"},
+}
+
+// FormatToolRef formats the Claude tools of a neutral tool as code spans
+func (c *Claude) FormatToolRef(tool string) (string, error) {
+	return formatToolRef(c.ID(), tool, claudeToolNames, codeSpan)
+}
+
+// claudeTools translates neutral tools to Claude tool rules.
+// For commands (preapproved), allowed lists the tools usable without asking; other tools are
+// asked for, and deny rules cannot be expressed. For subagents, allowed lists the available tools
+// and denied the tools the subagent must not use, while ask rules cannot be expressed.
+// Command patterns become Bash rules, "git *" being written as Bash(git:*).
+func claudeTools(source string, spec ToolSpec, preapproved bool) (allowed, denied []string) {
+	// addRule records the rules of a tool or command pattern for an action; listed rules are available
+	addRule := func(tool, action string, listed bool, rules ...string) {
+		switch action {
+		case PermissionAllow:
+			allowed = append(allowed, rules...)
+		case PermissionDeny:
+			if preapproved {
+				warnTool("claude", source, tool, "deny permissions cannot be expressed for commands and are ignored")
+				return
 			}
-		case strings.HasPrefix(line, "description: "):
-			val := strings.TrimSpace(strings.TrimPrefix(line, "description: "))
-			if val != "" && !strings.HasPrefix(val, "\"") && !strings.HasSuffix(val, "\"") {
-				line = "description: " + fmt.Sprintf("%q", val)
+			denied = append(denied, rules...)
+		case PermissionAsk:
+			// Commands ask for tools that are not pre-approved
+			if preapproved {
+				return
 			}
-		case strings.HasPrefix(strings.TrimLeft(line, " "), "- "):
-			// Quote list items under tools
-			trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimLeft(line, " "), "- "))
-			if trimmed != "" && !strings.HasPrefix(trimmed, "\"") && !strings.HasSuffix(trimmed, "\"") {
-				indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
-				line = indent + "- " + fmt.Sprintf("%q", trimmed)
+			warnTool("claude", source, tool, "ask permissions cannot be expressed for subagents and are ignored")
+			if listed {
+				allowed = append(allowed, rules...)
 			}
 		}
-		out.WriteString(line)
-		if i < len(lines)-1 {
-			out.WriteByte('\n')
+	}
+
+	for _, tool := range neutralTools {
+		action := spec.action(tool)
+		names, ok := claudeToolNames[tool]
+		if !ok {
+			if spec.listed(tool) || action != "" {
+				warnTool("claude", source, tool, "the tool cannot be expressed and is ignored")
+			}
+			continue
+		}
+		if action == "" && spec.listed(tool) {
+			action = PermissionAllow
+		}
+		// A subagent limited to allowed command patterns lists only those, as deny rules take precedence
+		if tool == ToolCommand && action == PermissionDeny && !preapproved && spec.allowsCommandPattern() {
+			action = ""
+		}
+		addRule(tool, action, spec.listed(tool), names...)
+		if tool != ToolCommand {
+			continue
+		}
+		for _, pattern := range spec.commandPatterns() {
+			patternAction := spec.Permissions[ToolCommand][pattern]
+			// Patterns allowed by an allowed Bash are already covered
+			if patternAction == PermissionAllow && action == PermissionAllow {
+				continue
+			}
+			addRule(tool, patternAction, true, claudeBashRule(pattern))
 		}
 	}
+	return allowed, denied
+}
 
-	frontmatter := "---\n" + out.String() + "\n---\n\n"
-	return frontmatter + mode.Content, nil
+// claudeBashRule converts a command pattern to a Claude Bash permission rule
+func claudeBashRule(pattern string) string {
+	if prefix, ok := strings.CutSuffix(pattern, " *"); ok {
+		return "Bash(" + prefix + ":*)"
+	}
+	return "Bash(" + pattern + ")"
 }
 
 // ModePath returns the default path for Claude agent mode files
@@ -1,7 +1,10 @@
 package agent
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
 )
 
 func TestClaude_ID_Name(t *testing.T) {
@@ -15,3 +18,138 @@ func TestClaude_ID_Name(t *testing.T) {
 }
 
 // ShouldConcatenate method has been removed as part of transition to path-based concatenation behavior
+
+// parseModes parses a single mode source the way the mode processor does.
+func parseModes(t *testing.T, content string) []model.Mode {
+	t.Helper()
+	m, err := model.ParseMode("mode.md", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseMode() error = %v", err)
+	}
+	return []model.Mode{*m}
+}
+
+func TestClaude_FormatMode(t *testing.T) {
+	c := &Claude{}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "all supported fields in canonical order",
+			content: "---\nclaude:\n  color: blue\n  model: sonnet\n  tools:\n    - Read\n    - Grep\n  name: reviewer\n  description: Reviews code\n  permissionMode: plan\n---\nReview the diff.",
+			want:    "---\nname: reviewer\ndescription: Reviews code\ntools:\n  - Read\n  - Grep\nmodel: sonnet\npermissionMode: plan\ncolor: blue\n---\n\nReview the diff.",
+		},
+		{
+			name:    "comma separated tools and common description",
+			content: "---\ndescription: Writes tests\nclaude:\n  name: tester\n  tools: Read, Edit\n---\nWrite tests.",
+			want:    "---\nname: tester\ndescription: Writes tests\ntools: Read, Edit\n---\n\nWrite tests.",
+		},
+		{
+			name:    "unknown keys are passed through after known keys",
+			content: "---\nclaude:\n  name: tester\n  description: Writes tests\n  zeta: 1\n  alpha: true\n---\nBody",
+			want:    "---\nname: tester\ndescription: Writes tests\nalpha: true\nzeta: 1\n---\n\nBody",
+		},
+		{
+			name:    "missing name",
+			content: "---\nclaude:\n  description: Writes tests\n---\nBody",
+			wantErr: "requires claude.name",
+		},
+		{
+			name:    "non-string tools",
+			content: "---\nclaude:\n  name: tester\n  description: Writes tests\n  tools:\n    - key: value\n---\nBody",
+			wantErr: "claude.tools must contain only strings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.FormatMode(parseModes(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FormatMode() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatMode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaude_FormatMode_MultilineDescription(t *testing.T) {
+	c := &Claude{}
+	description := "Use this agent when:\n- the user asks for a review\n- \"quotes\" and: colons appear"
+	mode := model.Mode{
+		Raw: map[string]any{
+			"claude": map[string]any{"name": "reviewer", "description": description},
+		},
+		Content: "Body",
+	}
+	got, err := c.FormatMode([]model.Mode{mode})
+	if err != nil {
+		t.Fatalf("FormatMode() error = %v", err)
+	}
+
+	parsed, err := model.ParseMode("reviewer.md", []byte(got))
+	if err != nil {
+		t.Fatalf("generated subagent does not parse: %v\n%s", err, got)
+	}
+	if parsed.Description != description {
+		t.Errorf("description round-trip = %q, want %q", parsed.Description, description)
+	}
+}
+
+func TestClaude_FormatSkill(t *testing.T) {
+	c := &Claude{}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "name defaults to directory",
+			content: "---\ndescription: Work with PDF files\n---\n# PDF",
+			want:    "---\nname: pdf\ndescription: Work with PDF files\n---\n\n# PDF",
+		},
+		{
+			name:    "claude section overrides and allowed tools list",
+			content: "---\nname: pdf\ndescription: Generic\nclaude:\n  description: Claude specific\n  allowed-tools:\n    - Read\n  model: haiku\n---\n# PDF",
+			want:    "---\nname: pdf\ndescription: Claude specific\nallowed-tools:\n  - Read\nmodel: haiku\n---\n\n# PDF",
+		},
+		{
+			name:    "missing description",
+			content: "# PDF",
+			wantErr: "requires a description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skill, err := model.ParseSkill("skills/pdf/SKILL.md", []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseSkill() error = %v", err)
+			}
+			got, err := c.FormatSkill(*skill)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FormatSkill() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatSkill() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatSkill() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
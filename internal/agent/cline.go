@@ -33,12 +33,22 @@ func (c *Cline) FormatMCP(agent, command string, args ...string) string {
 	return formatMCP(agent, command, args...)
 }
 
-// FormatMemory processes a memory context for Cline agent
-func (c *Cline) FormatMemory(content string) (string, error) {
+// FormatCommandRef formats a Cline workflow invocation; workflows are invoked by file name
+func (c *Cline) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + filepath.Base(command.Path), nil
+}
+
+// FormatModeRef rejects mode references, as Cline has no custom modes
+func (c *Cline) FormatModeRef(mode model.Mode) (string, error) {
+	return "", errors.New("cline agent does not support mode references")
+}
+
+// FormatMemory processes memory contexts for Cline agent
+func (c *Cline) FormatMemory(memories []model.Memory) (string, error) {
 	// Process the memory context for Cline
 	// For now, we're simply returning the content as is
 	// Any Cline-specific formatting rules could be applied here
-	return content, nil
+	return joinMemories(memories), nil
 }
 
 // FormatCommand processes command (workflow) definitions for Cline agent
@@ -50,6 +60,7 @@ func (c *Cline) FormatCommand(commands []model.Command) (string, error) {
 
 	// Cline workflows are plain markdown; just return the first command's content.
 	// Agent-specific fields (if any) are not used for Cline.
+	warnToolsIgnored(c.ID(), commands[0].Path, commands[0].Raw)
 	return commands[0].Content, nil
 }
 
@@ -80,6 +91,7 @@ func (c *Cline) FormatMode(modes []model.Mode) (string, error) {
 		return "", nil
 	}
 	// Single mode: return its content as-is
+	warnToolsIgnored(c.ID(), modes[0].Path, modes[0].Raw)
 	return modes[0].Content, nil
 }
 
@@ -87,3 +99,37 @@ func (c *Cline) FormatMode(modes []model.Mode) (string, error) {
 func (c *Cline) ModePath(userScope bool) string {
 	return ""
 }
+
+// FormatMCPConfig renders the Cline MCP configuration (cline_mcp_settings.json)
+func (c *Cline) FormatMCPConfig(servers []model.MCPServer) (string, error) {
+	return renderMCPConfig(c.ID(), "mcpServers", servers, mcpEntryOptions{
+		types: map[string]string{
+			model.MCPTypeHTTP: "streamableHttp",
+			model.MCPTypeSSE:  "sse",
+		},
+		disabledKey: "disabled",
+	})
+}
+
+// MCPConfigPath returns the default path for the Cline MCP configuration
+// Cline only reads MCP servers from VS Code globalStorage, so there is no project-scope default.
+func (c *Cline) MCPConfigPath(userScope bool) string {
+	if !userScope {
+		return ""
+	}
+	return vscodeUserPath("globalStorage", "saoudrizwan.claude-dev", "settings", "cline_mcp_settings.json")
+}
+
+// FormatIgnore renders the Cline ignore file (.clineignore)
+func (c *Cline) FormatIgnore(patterns []string) (string, error) {
+	return formatIgnoreFile(patterns), nil
+}
+
+// IgnorePath returns the default path for the Cline ignore file
+// The ignore file is only read from the workspace root, so there is no user-scope default.
+func (c *Cline) IgnorePath(userScope bool) string {
+	if userScope {
+		return ""
+	}
+	return ".clineignore"
+}
@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Codex implements the conversion logic for OpenAI Codex and other AGENTS.md readers
+type Codex struct{}
+
+// ID returns the unique identifier for Codex agent
+func (c *Codex) ID() string {
+	return "codex"
+}
+
+// Name returns the display name for Codex agent
+func (c *Codex) Name() string {
+	return "Codex"
+}
+
+// FormatFile converts a path to Codex's file reference format
+func (c *Codex) FormatFile(path string) string {
+	// Codex has no special reference syntax; use a plain code span
+	return fmt.Sprintf("`%s`", path)
+}
+
+// FormatMCP formats an MCP command for Codex agent
+func (c *Codex) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef formats a Codex custom prompt invocation
+func (c *Codex) FormatCommandRef(command model.Command) (string, error) {
+	return "/prompts:" + SourceName(command.Path), nil
+}
+
+// FormatModeRef rejects mode references, as Codex does not support modes
+func (c *Codex) FormatModeRef(mode model.Mode) (string, error) {
+	return "", fmt.Errorf("codex agent does not support modes")
+}
+
+// FormatMemory processes memory contexts for Codex agent
+func (c *Codex) FormatMemory(memories []model.Memory) (string, error) {
+	// AGENTS.md is plain markdown; return the content as is
+	return joinMemories(memories), nil
+}
+
+// MemoryDir returns the subdirectory for a memory source based on its top-level 'path' frontmatter key.
+// Codex reads nested AGENTS.md files, so each package of a monorepo can get its own memory.
+func (c *Codex) MemoryDir(fm map[string]any) (string, error) {
+	v, ok := fm["path"]
+	if !ok {
+		return "", nil
+	}
+	dir, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("memory frontmatter 'path' must be a string, got %T", v)
+	}
+	if filepath.IsAbs(dir) {
+		return "", fmt.Errorf("memory frontmatter 'path' must be relative to the output directory: %s", dir)
+	}
+	dir = filepath.Clean(dir)
+	if dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("memory frontmatter 'path' must not point outside the output directory: %s", v)
+	}
+	// "." and "./" name the output directory itself, as does no path
+	if dir == "." {
+		return "", nil
+	}
+	return dir, nil
+}
+
+// codexPromptMeta is the frontmatter supported by Codex custom prompts
+type codexPromptMeta struct {
+	Description  string `yaml:"description,omitempty"`
+	ArgumentHint string `yaml:"argument-hint,omitempty"`
+}
+
+// FormatCommand processes command definitions for Codex agent (custom prompts)
+func (c *Codex) FormatCommand(commands []model.Command) (string, error) {
+	var outputs []string
+	for _, cmd := range commands {
+		var meta codexPromptMeta
+		// Populate from codex section. Ignore error if section is missing.
+		_ = cmd.UnmarshalSection("codex", &meta)
+		warnToolsIgnored(c.ID(), cmd.Path, cmd.Raw)
+
+		// Priority: codex.description > top-level cmd.Description
+		if meta.Description == "" {
+			meta.Description = cmd.Description
+		}
+
+		if meta.Description == "" && meta.ArgumentHint == "" {
+			outputs = append(outputs, cmd.Content)
+			continue
+		}
+		yamlWithFences, err := frontmatter.Wrap(meta)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal codex prompt frontmatter: %w", err)
+		}
+		outputs = append(outputs, yamlWithFences+cmd.Content)
+	}
+	return strings.Join(outputs, "\n\n"), nil
+}
+
+// MemoryPath returns the default path for Codex agent memory files
+func (c *Codex) MemoryPath(userScope bool) string {
+	if userScope {
+		return ".codex/AGENTS.md"
+	}
+	return "AGENTS.md"
+}
+
+// CommandPath returns the default path for Codex agent command files.
+// Codex only reads prompts from ~/.codex/prompts/, so project scope uses the same layout.
+func (c *Codex) CommandPath(userScope bool) string {
+	return ".codex/prompts/"
+}
+
+// FormatMode processes mode definitions for Codex agent
+func (c *Codex) FormatMode(modes []model.Mode) (string, error) {
+	return "", fmt.Errorf("codex agent does not support modes")
+}
+
+// ModePath returns the default path for Codex agent mode files
+func (c *Codex) ModePath(userScope bool) string {
+	return ""
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestCodex_ID_Name(t *testing.T) {
+	c := &Codex{}
+	if c.ID() != "codex" {
+		t.Errorf("expected ID 'codex', got %q", c.ID())
+	}
+	if c.Name() != "Codex" {
+		t.Errorf("expected Name 'Codex', got %q", c.Name())
+	}
+}
+
+func TestCodex_Paths(t *testing.T) {
+	c := &Codex{}
+	if got := c.MemoryPath(false); got != "AGENTS.md" {
+		t.Errorf("MemoryPath(false) = %q, want %q", got, "AGENTS.md")
+	}
+	if got := c.CommandPath(true); got != ".codex/prompts/" {
+		t.Errorf("CommandPath(true) = %q, want %q", got, ".codex/prompts/")
+	}
+}
+
+func TestCodex_MemoryDir(t *testing.T) {
+	c := &Codex{}
+
+	tests := []struct {
+		name    string
+		fm      map[string]any
+		want    string
+		wantErr bool
+	}{
+		{name: "no path", fm: map[string]any{}, want: ""},
+		{name: "nested path", fm: map[string]any{"path": "packages/api/"}, want: "packages/api"},
+		{name: "current directory", fm: map[string]any{"path": "."}, want: ""},
+		{name: "current directory with slash", fm: map[string]any{"path": "./"}, want: ""},
+		{name: "absolute path", fm: map[string]any{"path": "/etc"}, wantErr: true},
+		{name: "parent path", fm: map[string]any{"path": "../other"}, wantErr: true},
+		{name: "path escaping via dots", fm: map[string]any{"path": "packages/../.."}, wantErr: true},
+		{name: "non-string path", fm: map[string]any{"path": 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.MemoryDir(tt.fm)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MemoryDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MemoryDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodex_FormatCommand(t *testing.T) {
+	c := &Codex{}
+
+	got, err := c.FormatCommand([]model.Command{{
+		Description: "Review changes",
+		Content:     "Review $ARGUMENTS",
+		Raw: map[string]any{
+			"codex": map[string]any{"argument-hint": "[path]"},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	want := "---\ndescription: Review changes\nargument-hint: \"[path]\"\n---\n\nReview $ARGUMENTS"
+	if got != want {
+		t.Errorf("FormatCommand() = %q, want %q", got, want)
+	}
+
+	got, err = c.FormatCommand([]model.Command{{Content: "Plain"}})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	if got != "Plain" {
+		t.Errorf("FormatCommand() = %q, want %q", got, "Plain")
+	}
+}
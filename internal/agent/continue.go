@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Continue implements the Continue-specific conversion logic
+type Continue struct {
+	plainMarkdown
+}
+
+// ID returns the unique identifier for Continue agent
+func (c *Continue) ID() string {
+	return "continue"
+}
+
+// Name returns the display name for Continue agent
+func (c *Continue) Name() string {
+	return "Continue"
+}
+
+// ContinuePromptMeta is the frontmatter of a Continue .prompt file
+type ContinuePromptMeta struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Invokable   *bool  `yaml:"invokable,omitempty"`
+}
+
+// FormatCommandRef formats a Continue prompt invocation by the prompt's name
+func (c *Continue) FormatCommandRef(command model.Command) (string, error) {
+	var meta ContinuePromptMeta
+	_ = command.UnmarshalSection("continue", &meta)
+	if meta.Name == "" {
+		meta.Name = SourceName(command.Path)
+	}
+	return "/" + meta.Name, nil
+}
+
+// FormatCommand renders command definitions as Continue prompt files
+func (c *Continue) FormatCommand(commands []model.Command) (string, error) {
+	var outputs []string
+	for _, cmd := range commands {
+		var meta ContinuePromptMeta
+		// Populate from continue section. Ignore error if section is missing.
+		_ = cmd.UnmarshalSection("continue", &meta)
+		warnToolsIgnored(c.ID(), cmd.Path, cmd.Raw)
+
+		// Name defaults to the source file name so the prompt is invoked like other agents' commands
+		if meta.Name == "" && cmd.Path != "" {
+			meta.Name = SourceName(cmd.Path)
+		}
+		if meta.Name == "" {
+			return "", fmt.Errorf("continue prompt requires a name: provide 'continue.name'")
+		}
+		// Priority: continue.description > top-level cmd.Description
+		if meta.Description == "" {
+			meta.Description = cmd.Description
+		}
+		// Prompts are slash commands unless explicitly disabled
+		if meta.Invokable == nil {
+			invokable := true
+			meta.Invokable = &invokable
+		}
+
+		yamlWithFences, err := frontmatter.Wrap(meta)
+		if err != nil {
+			return "", fmt.Errorf("failed to render continue prompt frontmatter: %w", err)
+		}
+		outputs = append(outputs, yamlWithFences+cmd.Content)
+	}
+	return strings.Join(outputs, "\n\n"), nil
+}
+
+// MemoryPath returns the default path for Continue agent memory (rule) files
+func (c *Continue) MemoryPath(userScope bool) string {
+	return ".continue/rules/"
+}
+
+// CommandPath returns the default path for Continue agent command (prompt) files
+func (c *Continue) CommandPath(userScope bool) string {
+	return ".continue/prompts/"
+}
+
+// FileName returns the output file name for Continue; prompts use the .prompt extension
+func (c *Continue) FileName(taskType string, inputPath string) string {
+	base := filepath.Base(inputPath)
+	if taskType == "command" {
+		return strings.TrimSuffix(base, filepath.Ext(base)) + ".prompt"
+	}
+	return base
+}
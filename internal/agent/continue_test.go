@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestContinue_FormatCommand(t *testing.T) {
+	c := &Continue{}
+
+	tests := []struct {
+		name string
+		cmd  model.Command
+		want string
+	}{
+		{
+			name: "defaults from path and description",
+			cmd: model.Command{
+				Path:        "/src/commands/review.md",
+				Description: "Review code",
+				Content:     "Review the selection.",
+				Raw:         map[string]any{},
+			},
+			want: "---\nname: review\ndescription: Review code\ninvokable: true\n---\n\nReview the selection.",
+		},
+		{
+			name: "continue section overrides",
+			cmd: model.Command{
+				Path:        "/src/commands/review.md",
+				Description: "Review code",
+				Content:     "Body",
+				Raw: map[string]any{
+					"continue": map[string]any{
+						"name":        "code-review",
+						"description": "Continue review",
+						"invokable":   false,
+					},
+				},
+			},
+			want: "---\nname: code-review\ndescription: Continue review\ninvokable: false\n---\n\nBody",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.FormatCommand([]model.Command{tt.cmd})
+			if err != nil {
+				t.Fatalf("FormatCommand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContinue_FormatCommand_MissingName(t *testing.T) {
+	c := &Continue{}
+	if _, err := c.FormatCommand([]model.Command{{Content: "Body"}}); err == nil {
+		t.Errorf("expected error when name cannot be determined")
+	}
+}
+
+func TestContinue_FileName(t *testing.T) {
+	c := &Continue{}
+	if got := c.FileName("command", "commands/review.md"); got != "review.prompt" {
+		t.Errorf("FileName(command) = %q, want %q", got, "review.prompt")
+	}
+	if got := c.FileName("memory", "memories/style.md"); got != "style.md" {
+		t.Errorf("FileName(memory) = %q, want %q", got, "style.md")
+	}
+}
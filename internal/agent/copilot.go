@@ -35,11 +35,61 @@ func (c *Copilot) FormatMCP(agent, command string, args ...string) string {
 	return formatMCP(agent, command, args...)
 }
 
-// FormatMemory processes a memory context for Copilot agent
-func (c *Copilot) FormatMemory(content string) (string, error) {
+// FormatCommandRef formats a Copilot prompt file invocation
+func (c *Copilot) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + strings.TrimSuffix(SourceName(command.Path), ".prompt"), nil
+}
+
+// FormatModeRef formats a Copilot custom chat mode reference by its name
+func (c *Copilot) FormatModeRef(mode model.Mode) (string, error) {
+	return fmt.Sprintf("`%s`", strings.TrimSuffix(SourceName(mode.Path), ".chatmode")), nil
+}
+
+// FormatMemory processes memory contexts for Copilot agent
+func (c *Copilot) FormatMemory(memories []model.Memory) (string, error) {
 	// For global instructions, simply return the content as is
 	// No frontmatter is needed
-	return content, nil
+	return joinMemories(memories), nil
+}
+
+// CopilotInstructions is the frontmatter of a path-scoped *.instructions.md file
+type CopilotInstructions struct {
+	ApplyTo     string `yaml:"applyTo"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// FormatMemoryFile renders a memory context as a path-scoped instructions file.
+// applyTo is read from the 'copilot' section and defaults to all files.
+func (c *Copilot) FormatMemoryFile(memory model.Memory) (string, error) {
+	var src struct {
+		ApplyTo     any    `yaml:"applyTo"`
+		Description string `yaml:"description"`
+	}
+	if err := unmarshalSection(memory.Raw, "copilot", &src); err != nil {
+		return "", fmt.Errorf("copilot frontmatter parse error: %w", err)
+	}
+	applyTo, err := joinList("copilot.applyTo", src.ApplyTo)
+	if err != nil {
+		return "", err
+	}
+	if applyTo == "" {
+		applyTo = "**"
+	}
+
+	fm := CopilotInstructions{
+		ApplyTo:     applyTo,
+		Description: src.Description,
+	}
+	// Fallback to common top-level description field on Memory
+	if fm.Description == "" {
+		fm.Description = memory.Description
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(fm)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal copilot instructions frontmatter: %w", err)
+	}
+	return yamlWithFences + strings.TrimLeft(memory.Content, "\n"), nil
 }
 
 // FormatCommand processes command definitions for Copilot agent
@@ -78,6 +128,14 @@ func (c *Copilot) FormatCommand(commands []model.Command) (string, error) {
 	if fm.Description == "" && cmd.Description != "" {
 		fm.Description = cmd.Description
 	}
+	// Fallback to the neutral tools when copilot.tools is not provided
+	if len(fm.Tools) == 0 {
+		spec, err := parseToolSpec(cmd.Path, cmd.Raw)
+		if err != nil {
+			return "", fmt.Errorf("copilot command %s: %w", cmd.Path, err)
+		}
+		fm.Tools = translateToolList(c.ID(), cmd.Path, spec, copilotToolNames)
+	}
 
 	// Emit frontmatter only when at least one field is present.
 	if fm.Mode == "" && fm.Model == "" && len(fm.Tools) == 0 && fm.Description == "" {
@@ -154,7 +212,30 @@ func homeDir() string {
 	return home
 }
 
-// FormatMode processes mode definitions for Copilot agent
+// copilotToolNames maps neutral tools to Copilot chat tools
+var copilotToolNames = map[string][]string{
+	ToolRead:    {"codebase"},
+	ToolSearch:  {"search"},
+	ToolEdit:    {"editFiles"},
+	ToolCommand: {"runCommands"},
+	ToolWeb:     {"fetch"},
+}
+
+// FormatToolRef formats the Copilot tools of a neutral tool as #-references, as used in chat prompts
+func (c *Copilot) FormatToolRef(tool string) (string, error) {
+	return formatToolRef(c.ID(), tool, copilotToolNames, func(name string) string {
+		return "#" + name
+	})
+}
+
+// CopilotChatMode is the frontmatter of a VS Code custom chat mode file
+type CopilotChatMode struct {
+	Description string   `yaml:"description,omitempty"`
+	Tools       []string `yaml:"tools,omitempty"`
+	Model       string   `yaml:"model,omitempty"`
+}
+
+// FormatMode processes mode definitions for Copilot agent (custom chat modes)
 func (c *Copilot) FormatMode(modes []model.Mode) (string, error) {
 	// Copilot does not support multiple modes combined
 	if len(modes) > 1 {
@@ -164,11 +245,85 @@ func (c *Copilot) FormatMode(modes []model.Mode) (string, error) {
 	if len(modes) == 0 {
 		return "", nil
 	}
-	// Single mode: return its content
-	return modes[0].Content, nil
+
+	mode := modes[0]
+
+	// Read the nested "copilot" section, as FormatCommand does
+	var cm CopilotChatMode
+	if mode.Raw != nil {
+		if err := mode.UnmarshalSection("copilot", &cm); err != nil && err.Error() != "frontmatter missing section \"copilot\"" {
+			// Only ignore "missing section" errors. Propagate other marshal/unmarshal failures.
+			return "", fmt.Errorf("copilot frontmatter parse error: %w", err)
+		}
+	}
+
+	// Fallback to common top-level description field on Mode
+	if cm.Description == "" {
+		cm.Description = mode.Description
+	}
+	// Fallback to the neutral tools when copilot.tools is not provided
+	if len(cm.Tools) == 0 {
+		spec, err := parseToolSpec(mode.Path, mode.Raw)
+		if err != nil {
+			return "", fmt.Errorf("copilot chat mode %s: %w", mode.Path, err)
+		}
+		cm.Tools = translateToolList(c.ID(), mode.Path, spec, copilotToolNames)
+	}
+
+	body := strings.TrimLeft(mode.Content, "\n")
+
+	// Emit frontmatter only when at least one field is present.
+	if cm.Description == "" && len(cm.Tools) == 0 && cm.Model == "" {
+		return body, nil
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(cm)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal copilot chat mode frontmatter: %w", err)
+	}
+	return yamlWithFences + body, nil
 }
 
-// ModePath returns the default path for Copilot agent mode files
+// ModePath returns the default path for Copilot agent mode (custom chat mode) files
 func (c *Copilot) ModePath(userScope bool) string {
-	return ""
+	if userScope {
+		// User chat modes are read from the prompts folder of the VS Code user directory
+		return vscodeUserPath("prompts") + "/"
+	}
+	return filepath.Join(".github", "chatmodes") + "/"
+}
+
+// FormatMCPConfig renders the VS Code MCP configuration (mcp.json) used by Copilot
+func (c *Copilot) FormatMCPConfig(servers []model.MCPServer) (string, error) {
+	return renderMCPConfig(c.ID(), "servers", servers, mcpEntryOptions{
+		types: map[string]string{
+			model.MCPTypeStdio: "stdio",
+			model.MCPTypeHTTP:  "http",
+			model.MCPTypeSSE:   "sse",
+		},
+	})
+}
+
+// MCPConfigPath returns the default path for the Copilot MCP configuration
+// Project scope uses ".vscode/mcp.json"; user scope uses mcp.json in the VS Code user directory
+func (c *Copilot) MCPConfigPath(userScope bool) string {
+	if userScope {
+		return vscodeUserPath("mcp.json")
+	}
+	return ".vscode/mcp.json"
+}
+
+// FileName returns the output file name for Copilot.
+// Chat modes use the .chatmode.md naming and per-file instructions use .instructions.md.
+func (c *Copilot) FileName(taskType string, inputPath string) string {
+	base := filepath.Base(inputPath)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	switch taskType {
+	case "mode":
+		return strings.TrimSuffix(name, ".chatmode") + ".chatmode.md"
+	case "memory":
+		return strings.TrimSuffix(name, ".instructions") + ".instructions.md"
+	default:
+		return base
+	}
 }
@@ -66,7 +66,7 @@ func TestCopilot_FormatMemory(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := c.FormatMemory(tt.content)
+			result, err := c.FormatMemory([]model.Memory{{Content: tt.content}})
 			if err != nil {
 				t.Fatalf("FormatMemory returned unexpected error: %v", err)
 			}
@@ -77,6 +77,38 @@ func TestCopilot_FormatMemory(t *testing.T) {
 	}
 }
 
+func TestCopilot_FormatMemoryFile(t *testing.T) {
+	c := &Copilot{}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no frontmatter applies to all files",
+			content: "Use tabs.",
+			want:    "---\napplyTo: \"**\"\n---\n\nUse tabs.",
+		},
+		{
+			name:    "applyTo list and description",
+			content: "---\ndescription: Go rules\ncopilot:\n  applyTo:\n    - \"**/*.go\"\n    - go.mod\n---\nUse gofmt.",
+			want:    "---\napplyTo: \"**/*.go,go.mod\"\ndescription: Go rules\n---\n\nUse gofmt.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.FormatMemoryFile(parseMemories(t, tt.content)[0])
+			if err != nil {
+				t.Fatalf("FormatMemoryFile() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMemoryFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCopilot_FormatCommand(t *testing.T) {
 	c := &Copilot{}
 
@@ -333,3 +365,68 @@ func TestCopilot_FormatMCP(t *testing.T) {
 		})
 	}
 }
+
+func TestCopilot_FormatMode(t *testing.T) {
+	c := &Copilot{}
+
+	t.Run("chat mode frontmatter", func(t *testing.T) {
+		mode := model.Mode{
+			Description: "Top-level description",
+			Raw: map[string]any{
+				"copilot": map[string]any{
+					"description": "Plan changes",
+					"tools":       []any{"codebase", "search"},
+					"model":       "Claude Sonnet 4",
+				},
+			},
+			Content: "\nYou plan changes without editing files.",
+		}
+		result, err := c.FormatMode([]model.Mode{mode})
+		if err != nil {
+			t.Fatalf("FormatMode returned unexpected error: %v", err)
+		}
+		expected := "---\ndescription: Plan changes\ntools:\n  - codebase\n  - search\nmodel: Claude Sonnet 4\n---\n\nYou plan changes without editing files."
+		if result != expected {
+			t.Errorf("FormatMode() = %q, want %q", result, expected)
+		}
+	})
+
+	t.Run("description fallback", func(t *testing.T) {
+		mode := model.Mode{Description: "Review", Raw: map[string]any{"description": "Review"}, Content: "Body"}
+		result, err := c.FormatMode([]model.Mode{mode})
+		if err != nil {
+			t.Fatalf("FormatMode returned unexpected error: %v", err)
+		}
+		if result != "---\ndescription: Review\n---\n\nBody" {
+			t.Errorf("FormatMode() = %q", result)
+		}
+	})
+
+	t.Run("multiple modes", func(t *testing.T) {
+		if _, err := c.FormatMode([]model.Mode{{}, {}}); err == nil {
+			t.Errorf("expected error for multiple modes")
+		}
+	})
+}
+
+func TestCopilot_ModePath(t *testing.T) {
+	c := &Copilot{}
+	if got, want := c.ModePath(false), filepath.Join(".github", "chatmodes")+"/"; got != want {
+		t.Errorf("ModePath(false) = %q, want %q", got, want)
+	}
+	if got, want := c.ModePath(true), vscodeUserPath("prompts")+"/"; got != want {
+		t.Errorf("ModePath(true) = %q, want %q", got, want)
+	}
+	if got := c.FileName("mode", "modes/planner.md"); got != "planner.chatmode.md" {
+		t.Errorf("FileName(mode) = %q, want %q", got, "planner.chatmode.md")
+	}
+	if got := c.FileName("mode", "modes/planner.chatmode.md"); got != "planner.chatmode.md" {
+		t.Errorf("FileName(mode) = %q, want %q", got, "planner.chatmode.md")
+	}
+	if got := c.FileName("command", "commands/deploy.md"); got != "deploy.md" {
+		t.Errorf("FileName(command) = %q, want %q", got, "deploy.md")
+	}
+	if got := c.FileName("memory", "memories/go.md"); got != "go.instructions.md" {
+		t.Errorf("FileName(memory) = %q, want %q", got, "go.instructions.md")
+	}
+}
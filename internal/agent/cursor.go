@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Cursor implements the Cursor-specific conversion logic
+type Cursor struct{}
+
+// ID returns the unique identifier for Cursor agent
+func (c *Cursor) ID() string {
+	return "cursor"
+}
+
+// Name returns the display name for Cursor agent
+func (c *Cursor) Name() string {
+	return "Cursor"
+}
+
+// FormatFile converts a path to Cursor's @file reference format
+func (c *Cursor) FormatFile(path string) string {
+	return "@" + path
+}
+
+// FormatMCP formats an MCP command for Cursor agent
+func (c *Cursor) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef formats a Cursor command invocation
+func (c *Cursor) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef rejects mode references, as Cursor custom modes are not generated
+func (c *Cursor) FormatModeRef(mode model.Mode) (string, error) {
+	return "", fmt.Errorf("cursor agent does not support modes")
+}
+
+// cursorRuleSource is the 'cursor' frontmatter section accepted on memory sources.
+// Globs may be written either as a single comma-separated string or as a list.
+type cursorRuleSource struct {
+	Description string `yaml:"description,omitempty"`
+	Globs       any    `yaml:"globs,omitempty"`
+	AlwaysApply *bool  `yaml:"alwaysApply,omitempty"`
+}
+
+// cursorRule is the frontmatter emitted into .mdc rule files
+type cursorRule struct {
+	Description string `yaml:"description"`
+	Globs       string `yaml:"globs"`
+	AlwaysApply bool   `yaml:"alwaysApply"`
+}
+
+// FormatMemory converts memory contexts into a Cursor .mdc rule.
+// Rule metadata is read from the 'cursor' section of the first memory's frontmatter.
+// Without any rule metadata the rule is always applied.
+func (c *Cursor) FormatMemory(memories []model.Memory) (string, error) {
+	if len(memories) == 0 {
+		return "", nil
+	}
+	first := memories[0]
+
+	var src cursorRuleSource
+	if err := unmarshalSection(first.Raw, "cursor", &src); err != nil {
+		return "", fmt.Errorf("cursor frontmatter parse error: %w", err)
+	}
+
+	globs, err := joinList("cursor.globs", src.Globs)
+	if err != nil {
+		return "", err
+	}
+
+	rule := cursorRule{
+		Description: src.Description,
+		Globs:       globs,
+	}
+	if src.AlwaysApply != nil {
+		rule.AlwaysApply = *src.AlwaysApply
+	} else {
+		// Neither globs nor description: there is no other way for Cursor to pick the rule up
+		rule.AlwaysApply = rule.Globs == "" && rule.Description == ""
+	}
+	// Fallback to common description if not provided under cursor
+	if rule.Description == "" {
+		rule.Description = first.Description
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(rule)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor rule frontmatter: %w", err)
+	}
+	return yamlWithFences + strings.TrimLeft(joinMemories(memories), "\n"), nil
+}
+
+// FormatCommand processes command definitions for Cursor agent.
+// Cursor commands are plain markdown without frontmatter.
+func (c *Cursor) FormatCommand(commands []model.Command) (string, error) {
+	contents := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		warnToolsIgnored(c.ID(), cmd.Path, cmd.Raw)
+		contents = append(contents, cmd.Content)
+	}
+	return strings.Join(contents, "\n\n"), nil
+}
+
+// MemoryPath returns the default path for Cursor agent memory files.
+// Cursor has no file-based user rules, so there is no default path in user scope.
+func (c *Cursor) MemoryPath(userScope bool) string {
+	if userScope {
+		return ""
+	}
+	return ".cursor/rules/"
+}
+
+// CommandPath returns the default path for Cursor agent command files
+func (c *Cursor) CommandPath(userScope bool) string {
+	return ".cursor/commands/"
+}
+
+// FormatMode processes mode definitions for Cursor agent
+func (c *Cursor) FormatMode(modes []model.Mode) (string, error) {
+	// Cursor custom modes are configured in the UI, not in files
+	return "", fmt.Errorf("cursor agent does not support modes")
+}
+
+// ModePath returns the default path for Cursor agent mode files
+func (c *Cursor) ModePath(userScope bool) string {
+	return ""
+}
+
+// FormatMCPConfig renders the Cursor MCP configuration (mcp.json)
+func (c *Cursor) FormatMCPConfig(servers []model.MCPServer) (string, error) {
+	return renderMCPConfig(c.ID(), "mcpServers", servers, mcpEntryOptions{})
+}
+
+// MCPConfigPath returns the default path for the Cursor MCP configuration
+func (c *Cursor) MCPConfigPath(userScope bool) string {
+	return ".cursor/mcp.json"
+}
+
+// FormatIgnore renders the Cursor ignore file (.cursorignore)
+func (c *Cursor) FormatIgnore(patterns []string) (string, error) {
+	return formatIgnoreFile(patterns), nil
+}
+
+// IgnorePath returns the default path for the Cursor ignore file
+// The ignore file is only read from the workspace root, so there is no user-scope default.
+func (c *Cursor) IgnorePath(userScope bool) string {
+	if userScope {
+		return ""
+	}
+	return ".cursorignore"
+}
+
+// FileName returns the output file name for Cursor; rules use the .mdc extension
+func (c *Cursor) FileName(taskType string, inputPath string) string {
+	base := filepath.Base(inputPath)
+	if taskType == "memory" {
+		return strings.TrimSuffix(base, filepath.Ext(base)) + ".mdc"
+	}
+	return base
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// parseMemories parses a single memory source the way the memory processor does.
+func parseMemories(t *testing.T, content string) []model.Memory {
+	t.Helper()
+	m, err := model.ParseMemory("memory.md", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseMemory() error = %v", err)
+	}
+	return []model.Memory{*m}
+}
+
+func TestCursor_ID_Name(t *testing.T) {
+	c := &Cursor{}
+	if c.ID() != "cursor" {
+		t.Errorf("expected ID 'cursor', got %q", c.ID())
+	}
+	if c.Name() != "Cursor" {
+		t.Errorf("expected Name 'Cursor', got %q", c.Name())
+	}
+}
+
+func TestCursor_FormatFile(t *testing.T) {
+	c := &Cursor{}
+	if got := c.FormatFile("src/main.go"); got != "@src/main.go" {
+		t.Errorf("FormatFile() = %q, want %q", got, "@src/main.go")
+	}
+}
+
+func TestCursor_MemoryPath(t *testing.T) {
+	c := &Cursor{}
+	if got := c.MemoryPath(false); got != ".cursor/rules/" {
+		t.Errorf("MemoryPath(false) = %q, want %q", got, ".cursor/rules/")
+	}
+	// Cursor has no file-based user rules
+	if got := c.MemoryPath(true); got != "" {
+		t.Errorf("MemoryPath(true) = %q, want empty", got)
+	}
+}
+
+func TestCursor_FormatMemory(t *testing.T) {
+	c := &Cursor{}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no frontmatter is always applied",
+			content: "# Rules\n\nBe nice.",
+			want:    "---\ndescription: \"\"\nglobs: \"\"\nalwaysApply: true\n---\n\n# Rules\n\nBe nice.",
+		},
+		{
+			name:    "globs list is joined",
+			content: "---\ncursor:\n  globs:\n    - \"src/**/*.ts\"\n    - \"src/**/*.tsx\"\n---\n\n# TS rules",
+			want:    "---\ndescription: \"\"\nglobs: src/**/*.ts,src/**/*.tsx\nalwaysApply: false\n---\n\n# TS rules",
+		},
+		{
+			name:    "description falls back to top-level",
+			content: "---\ndescription: Go style\ncursor:\n  globs: \"*.go\"\n---\nUse gofmt.",
+			want:    "---\ndescription: Go style\nglobs: \"*.go\"\nalwaysApply: false\n---\n\nUse gofmt.",
+		},
+		{
+			name:    "explicit alwaysApply",
+			content: "---\ncursor:\n  description: Agent requested\n  alwaysApply: true\n---\nBody",
+			want:    "---\ndescription: Agent requested\nglobs: \"\"\nalwaysApply: true\n---\n\nBody",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.FormatMemory(parseMemories(t, tt.content))
+			if err != nil {
+				t.Fatalf("FormatMemory() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMemory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursor_FormatMemory_InvalidGlobs(t *testing.T) {
+	c := &Cursor{}
+	_, err := c.FormatMemory(parseMemories(t, "---\ncursor:\n  globs:\n    key: value\n---\nBody"))
+	if err == nil {
+		t.Fatal("expected error for non-string globs, got nil")
+	}
+}
+
+func TestCursor_FormatCommand(t *testing.T) {
+	c := &Cursor{}
+	got, err := c.FormatCommand([]model.Command{
+		{Content: "# Deploy", Description: "ignored", Raw: map[string]any{"description": "ignored"}},
+	})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	if got != "# Deploy" {
+		t.Errorf("FormatCommand() = %q, want %q", got, "# Deploy")
+	}
+}
+
+func TestCursor_FileName(t *testing.T) {
+	c := &Cursor{}
+	if got := c.FileName("memory", "memories/go-style.md"); got != "go-style.mdc" {
+		t.Errorf("FileName(memory) = %q, want %q", got, "go-style.mdc")
+	}
+	if got := c.FileName("command", "commands/deploy.md"); got != "deploy.md" {
+		t.Errorf("FileName(command) = %q, want %q", got, "deploy.md")
+	}
+}
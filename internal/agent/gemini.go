@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Gemini implements the Gemini CLI-specific conversion logic
+type Gemini struct{}
+
+// ID returns the unique identifier for Gemini agent
+func (g *Gemini) ID() string {
+	return "gemini"
+}
+
+// Name returns the display name for Gemini agent
+func (g *Gemini) Name() string {
+	return "Gemini"
+}
+
+// FormatFile converts a path to Gemini's @file reference format
+func (g *Gemini) FormatFile(path string) string {
+	return "@" + path
+}
+
+// FormatMCP formats an MCP command for Gemini agent
+func (g *Gemini) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef formats a Gemini custom command invocation
+func (g *Gemini) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef rejects mode references, as Gemini does not support modes
+func (g *Gemini) FormatModeRef(mode model.Mode) (string, error) {
+	return "", fmt.Errorf("gemini agent does not support modes")
+}
+
+// FormatMemory processes memory contexts for Gemini agent
+func (g *Gemini) FormatMemory(memories []model.Memory) (string, error) {
+	// GEMINI.md is plain markdown; return the content as is
+	return joinMemories(memories), nil
+}
+
+// geminiArgumentReplacer maps Claude-style argument placeholders to Gemini's {{args}}
+var geminiArgumentReplacer = strings.NewReplacer("$ARGUMENTS", "{{args}}")
+
+// FormatCommand renders a command definition as a Gemini CLI TOML custom command
+func (g *Gemini) FormatCommand(commands []model.Command) (string, error) {
+	if len(commands) == 0 {
+		return "", nil
+	}
+
+	// Each Gemini custom command lives in its own TOML file
+	if len(commands) > 1 {
+		return "", fmt.Errorf("gemini agent does not support multiple commands in one file")
+	}
+
+	cmd := commands[0]
+	warnToolsIgnored(g.ID(), cmd.Path, cmd.Raw)
+
+	type geminiFm struct {
+		Description string `yaml:"description,omitempty"`
+	}
+	var fm geminiFm
+	if cmd.Raw != nil {
+		_ = cmd.UnmarshalSection("gemini", &fm)
+	}
+	// Fallback to common description if not provided under gemini
+	if fm.Description == "" {
+		fm.Description = cmd.Description
+	}
+
+	command := geminiCommand{
+		Description: fm.Description,
+		Prompt:      geminiArgumentReplacer.Replace(cmd.Content),
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(command); err != nil {
+		return "", fmt.Errorf("encode gemini command %s: %w", cmd.Path, err)
+	}
+	return buf.String(), nil
+}
+
+// geminiCommand is the TOML file of a Gemini CLI custom command
+type geminiCommand struct {
+	Description string `toml:"description,omitempty"`
+	Prompt      string `toml:"prompt"`
+}
+
+// MemoryPath returns the default path for Gemini agent memory files
+func (g *Gemini) MemoryPath(userScope bool) string {
+	if userScope {
+		return ".gemini/GEMINI.md"
+	}
+	return "GEMINI.md"
+}
+
+// CommandPath returns the default path for Gemini agent command files
+func (g *Gemini) CommandPath(userScope bool) string {
+	return ".gemini/commands/"
+}
+
+// FormatMode processes mode definitions for Gemini agent
+func (g *Gemini) FormatMode(modes []model.Mode) (string, error) {
+	return "", fmt.Errorf("gemini agent does not support modes")
+}
+
+// ModePath returns the default path for Gemini agent mode files
+func (g *Gemini) ModePath(userScope bool) string {
+	return ""
+}
+
+// FormatIgnore renders the Gemini ignore file (.aiexclude)
+func (g *Gemini) FormatIgnore(patterns []string) (string, error) {
+	return formatIgnoreFile(patterns), nil
+}
+
+// IgnorePath returns the default path for the Gemini ignore file
+// The ignore file is only read from the workspace, so there is no user-scope default.
+func (g *Gemini) IgnorePath(userScope bool) string {
+	if userScope {
+		return ""
+	}
+	return ".aiexclude"
+}
+
+// FileName returns the output file name for Gemini; custom commands use the .toml extension
+func (g *Gemini) FileName(taskType string, inputPath string) string {
+	base := filepath.Base(inputPath)
+	if taskType == "command" {
+		return strings.TrimSuffix(base, filepath.Ext(base)) + ".toml"
+	}
+	return base
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestGemini_ID_Name(t *testing.T) {
+	g := &Gemini{}
+	if g.ID() != "gemini" {
+		t.Errorf("expected ID 'gemini', got %q", g.ID())
+	}
+	if g.Name() != "Gemini" {
+		t.Errorf("expected Name 'Gemini', got %q", g.Name())
+	}
+}
+
+func TestGemini_FormatCommand(t *testing.T) {
+	g := &Gemini{}
+
+	tests := []struct {
+		name string
+		cmd  model.Command
+		want string
+	}{
+		{
+			name: "single line prompt without description",
+			cmd:  model.Command{Content: `Say "hi"`},
+			want: "prompt = \"Say \\\"hi\\\"\"\n",
+		},
+		{
+			name: "multi-line prompt with arguments",
+			cmd: model.Command{
+				Description: "Review a file",
+				Content:     "# Review\n\nReview $ARGUMENTS carefully.",
+				Raw:         map[string]any{},
+			},
+			want: "description = \"Review a file\"\nprompt = \"# Review\\n\\nReview {{args}} carefully.\"\n",
+		},
+		{
+			name: "gemini description overrides top-level",
+			cmd: model.Command{
+				Description: "Top",
+				Content:     "Body",
+				Raw: map[string]any{
+					"gemini": map[string]any{"description": "Gemini only"},
+				},
+			},
+			want: "description = \"Gemini only\"\nprompt = \"Body\"\n",
+		},
+		{
+			name: "control characters are escaped",
+			cmd:  model.Command{Content: "a\n''' \\d\x1b[0m"},
+			want: "prompt = \"a\\n''' \\\\d\\u001b[0m\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.FormatCommand([]model.Command{tt.cmd})
+			if err != nil {
+				t.Fatalf("FormatCommand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatCommand() = %q, want %q", got, tt.want)
+			}
+			var decoded geminiCommand
+			if _, err := toml.Decode(got, &decoded); err != nil {
+				t.Fatalf("output is not valid TOML: %v", err)
+			}
+			if want := geminiArgumentReplacer.Replace(tt.cmd.Content); decoded.Prompt != want {
+				t.Errorf("decoded prompt = %q, want %q", decoded.Prompt, want)
+			}
+		})
+	}
+}
+
+func TestGemini_FormatCommand_Multiple(t *testing.T) {
+	g := &Gemini{}
+	_, err := g.FormatCommand([]model.Command{{Content: "a"}, {Content: "b"}})
+	if err == nil || !strings.Contains(err.Error(), "does not support multiple commands") {
+		t.Errorf("expected multiple commands error, got %v", err)
+	}
+}
+
+func TestGemini_Paths(t *testing.T) {
+	g := &Gemini{}
+	if got := g.MemoryPath(false); got != "GEMINI.md" {
+		t.Errorf("MemoryPath(false) = %q", got)
+	}
+	if got := g.MemoryPath(true); got != ".gemini/GEMINI.md" {
+		t.Errorf("MemoryPath(true) = %q", got)
+	}
+	if got := g.CommandPath(true); got != ".gemini/commands/" {
+		t.Errorf("CommandPath(true) = %q", got)
+	}
+	if got := g.FileName("command", "commands/review.md"); got != "review.toml" {
+		t.Errorf("FileName(command) = %q, want %q", got, "review.toml")
+	}
+}
@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"testing"
+)
+
+func TestFormatIgnore(t *testing.T) {
+	patterns := []string{".env", "secrets/", "/build", "!secrets/README.md"}
+	tests := []struct {
+		agent IgnoreFormatter
+		path  string
+		want  string
+	}{
+		{&Roo{}, ".rooignore", ".env\nsecrets/\n/build\n!secrets/README.md\n"},
+		{&Cline{}, ".clineignore", ".env\nsecrets/\n/build\n!secrets/README.md\n"},
+		{&Cursor{}, ".cursorignore", ".env\nsecrets/\n/build\n!secrets/README.md\n"},
+		{&Gemini{}, ".aiexclude", ".env\nsecrets/\n/build\n!secrets/README.md\n"},
+		{
+			agent: &Claude{},
+			path:  ".claude/settings.json",
+			want:  "{\n  \"permissions\": {\n    \"deny\": [\n      \"Read(.env)\",\n      \"Read(secrets/**)\",\n      \"Read(./build)\"\n    ]\n  }\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.(Agent).ID(), func(t *testing.T) {
+			got, err := tt.agent.FormatIgnore(patterns)
+			if err != nil {
+				t.Fatalf("FormatIgnore() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatIgnore() = %q, want %q", got, tt.want)
+			}
+			if p := tt.agent.IgnorePath(false); p != tt.path {
+				t.Errorf("IgnorePath(false) = %q, want %q", p, tt.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Junie implements the JetBrains Junie-specific conversion logic
+type Junie struct {
+	plainMarkdown
+}
+
+// ID returns the unique identifier for Junie agent
+func (j *Junie) ID() string {
+	return "junie"
+}
+
+// Name returns the display name for Junie agent
+func (j *Junie) Name() string {
+	return "Junie"
+}
+
+// FormatCommand processes command definitions for Junie agent
+func (j *Junie) FormatCommand(commands []model.Command) (string, error) {
+	return "", fmt.Errorf("junie agent does not support commands")
+}
+
+// FormatCommandRef rejects command references, as Junie does not support commands
+func (j *Junie) FormatCommandRef(command model.Command) (string, error) {
+	return "", fmt.Errorf("junie agent does not support commands")
+}
+
+// MemoryPath returns the default path for Junie agent memory files.
+// Junie only reads project guidelines, so both scopes use the same layout.
+func (j *Junie) MemoryPath(userScope bool) string {
+	return ".junie/guidelines.md"
+}
+
+// CommandPath returns the default path for Junie agent command files
+func (j *Junie) CommandPath(userScope bool) string {
+	return ""
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Kiro steering inclusion modes
+const (
+	kiroInclusionAlways    = "always"
+	kiroInclusionFileMatch = "fileMatch"
+	kiroInclusionManual    = "manual"
+)
+
+// Kiro implements the Kiro-specific conversion logic
+type Kiro struct{}
+
+// ID returns the unique identifier for Kiro agent
+func (k *Kiro) ID() string {
+	return "kiro"
+}
+
+// Name returns the display name for Kiro agent
+func (k *Kiro) Name() string {
+	return "Kiro"
+}
+
+// FormatFile converts a path to Kiro's file reference format
+func (k *Kiro) FormatFile(path string) string {
+	// Steering files reference workspace files with #[[file:<path>]]
+	return fmt.Sprintf("#[[file:%s]]", path)
+}
+
+// FormatMCP formats an MCP command for Kiro agent
+func (k *Kiro) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef rejects command references, as Kiro does not support commands
+func (k *Kiro) FormatCommandRef(command model.Command) (string, error) {
+	return "", fmt.Errorf("kiro agent does not support commands")
+}
+
+// FormatModeRef rejects mode references, as Kiro does not support modes
+func (k *Kiro) FormatModeRef(mode model.Mode) (string, error) {
+	return "", fmt.Errorf("kiro agent does not support modes")
+}
+
+// KiroSteeringMeta is the frontmatter of a Kiro steering file
+type KiroSteeringMeta struct {
+	Inclusion        string `yaml:"inclusion"`
+	FileMatchPattern string `yaml:"fileMatchPattern,omitempty"`
+}
+
+// FormatMemory converts memory contexts into a Kiro steering file.
+// Inclusion settings are read from the 'kiro' section of the first memory's frontmatter;
+// sources without the section are always included.
+func (k *Kiro) FormatMemory(memories []model.Memory) (string, error) {
+	if len(memories) == 0 {
+		return "", nil
+	}
+
+	var meta KiroSteeringMeta
+	if err := unmarshalSection(memories[0].Raw, "kiro", &meta); err != nil {
+		return "", fmt.Errorf("kiro frontmatter parse error: %w", err)
+	}
+
+	switch meta.Inclusion {
+	case "":
+		if meta.FileMatchPattern != "" {
+			meta.Inclusion = kiroInclusionFileMatch
+		} else {
+			meta.Inclusion = kiroInclusionAlways
+		}
+	case kiroInclusionAlways, kiroInclusionManual:
+	case kiroInclusionFileMatch:
+		if meta.FileMatchPattern == "" {
+			return "", fmt.Errorf("kiro steering with inclusion %q requires 'kiro.fileMatchPattern'", kiroInclusionFileMatch)
+		}
+	default:
+		return "", fmt.Errorf("unsupported kiro inclusion %q: must be one of %s, %s, %s",
+			meta.Inclusion, kiroInclusionAlways, kiroInclusionFileMatch, kiroInclusionManual)
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(meta)
+	if err != nil {
+		return "", fmt.Errorf("failed to render kiro steering frontmatter: %w", err)
+	}
+	return yamlWithFences + strings.TrimLeft(joinMemories(memories), "\n"), nil
+}
+
+// FormatCommand processes command definitions for Kiro agent
+func (k *Kiro) FormatCommand(commands []model.Command) (string, error) {
+	return "", fmt.Errorf("kiro agent does not support commands")
+}
+
+// MemoryPath returns the default path for Kiro agent memory (steering) files
+func (k *Kiro) MemoryPath(userScope bool) string {
+	return ".kiro/steering/"
+}
+
+// CommandPath returns the default path for Kiro agent command files
+func (k *Kiro) CommandPath(userScope bool) string {
+	return ""
+}
+
+// FormatMode processes mode definitions for Kiro agent
+func (k *Kiro) FormatMode(modes []model.Mode) (string, error) {
+	return "", fmt.Errorf("kiro agent does not support modes")
+}
+
+// ModePath returns the default path for Kiro agent mode files
+func (k *Kiro) ModePath(userScope bool) string {
+	return ""
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKiro_ID_Name(t *testing.T) {
+	k := &Kiro{}
+	if k.ID() != "kiro" {
+		t.Errorf("expected ID 'kiro', got %q", k.ID())
+	}
+	if k.Name() != "Kiro" {
+		t.Errorf("expected Name 'Kiro', got %q", k.Name())
+	}
+}
+
+func TestKiro_FormatMemory(t *testing.T) {
+	k := &Kiro{}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "no section defaults to always",
+			content: "# Product\n\nOverview",
+			want:    "---\ninclusion: always\n---\n\n# Product\n\nOverview",
+		},
+		{
+			name:    "other agent sections are dropped",
+			content: "---\ndescription: x\nroo:\n  foo: bar\n---\nBody",
+			want:    "---\ninclusion: always\n---\n\nBody",
+		},
+		{
+			name:    "fileMatch with pattern",
+			content: "---\nkiro:\n  inclusion: fileMatch\n  fileMatchPattern: \"components/**/*.tsx\"\n---\nComponents",
+			want:    "---\ninclusion: fileMatch\nfileMatchPattern: components/**/*.tsx\n---\n\nComponents",
+		},
+		{
+			name:    "pattern implies fileMatch",
+			content: "---\nkiro:\n  fileMatchPattern: \"*.sql\"\n---\nSQL",
+			want:    "---\ninclusion: fileMatch\nfileMatchPattern: \"*.sql\"\n---\n\nSQL",
+		},
+		{
+			name:    "manual",
+			content: "---\nkiro:\n  inclusion: manual\n---\nManual",
+			want:    "---\ninclusion: manual\n---\n\nManual",
+		},
+		{
+			name:    "fileMatch without pattern",
+			content: "---\nkiro:\n  inclusion: fileMatch\n---\nBody",
+			wantErr: "requires 'kiro.fileMatchPattern'",
+		},
+		{
+			name:    "unknown inclusion",
+			content: "---\nkiro:\n  inclusion: auto\n---\nBody",
+			wantErr: "unsupported kiro inclusion",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := k.FormatMemory(parseMemories(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FormatMemory() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatMemory() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMemory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
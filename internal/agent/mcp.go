@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// mcpEntryOptions describes how an agent spells an MCP server entry
+type mcpEntryOptions struct {
+	// types maps neutral transport types to the agent's "type" value.
+	// A missing or empty value omits the "type" key.
+	types map[string]string
+	// disabledKey is the key used for disabled servers. When empty, disabled
+	// servers are left out of the configuration.
+	disabledKey string
+}
+
+// renderMCPConfig renders servers as a JSON object keyed by server name under rootKey.
+// Keys of the agent's section in a server definition (e.g. 'roo:') are added to its entry.
+func renderMCPConfig(agentID string, rootKey string, servers []model.MCPServer, opts mcpEntryOptions) (string, error) {
+	entries := make(map[string]any, len(servers))
+	for _, s := range servers {
+		if s.Disabled && opts.disabledKey == "" {
+			continue
+		}
+
+		entry := map[string]any{}
+		if t := opts.types[s.Type]; t != "" {
+			entry["type"] = t
+		}
+		if s.IsRemote() {
+			entry["url"] = s.URL
+			if len(s.Headers) > 0 {
+				entry["headers"] = s.Headers
+			}
+		} else {
+			entry["command"] = s.Command
+			if len(s.Args) > 0 {
+				entry["args"] = s.Args
+			}
+			if len(s.Env) > 0 {
+				entry["env"] = s.Env
+			}
+		}
+		if s.Disabled {
+			entry[opts.disabledKey] = true
+		}
+
+		var extra map[string]any
+		if err := unmarshalSection(s.Raw, agentID, &extra); err != nil {
+			return "", fmt.Errorf("MCP server %q: %w", s.Name, err)
+		}
+		for k, v := range extra {
+			entry[k] = v
+		}
+		entries[s.Name] = entry
+	}
+	return marshalJSON(map[string]any{rootKey: entries})
+}
+
+// marshalJSON renders v as indented JSON with a trailing newline, without HTML escaping
+func marshalJSON(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return buf.String(), nil
+}
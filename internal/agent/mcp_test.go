@@ -0,0 +1,159 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestFormatMCPConfig(t *testing.T) {
+	servers, err := model.ParseMCPServers("mcp.yml", []byte(`servers:
+  docs:
+    url: https://example.com/mcp
+    headers:
+      Authorization: "Bearer ${TOKEN}"
+  github:
+    command: npx
+    args: ["-y", "server-github"]
+    roo:
+      alwaysAllow: [list_issues]
+  legacy:
+    command: legacy-server
+    disabled: true
+`))
+	if err != nil {
+		t.Fatalf("ParseMCPServers() error = %v", err)
+	}
+
+	tests := []struct {
+		agent MCPConfigurator
+		want  string
+	}{
+		{
+			agent: &Claude{},
+			want: `{
+  "mcpServers": {
+    "docs": {
+      "headers": {
+        "Authorization": "Bearer ${TOKEN}"
+      },
+      "type": "http",
+      "url": "https://example.com/mcp"
+    },
+    "github": {
+      "args": [
+        "-y",
+        "server-github"
+      ],
+      "command": "npx",
+      "type": "stdio"
+    }
+  }
+}
+`,
+		},
+		{
+			agent: &Roo{},
+			want: `{
+  "mcpServers": {
+    "docs": {
+      "headers": {
+        "Authorization": "Bearer ${TOKEN}"
+      },
+      "type": "streamable-http",
+      "url": "https://example.com/mcp"
+    },
+    "github": {
+      "alwaysAllow": [
+        "list_issues"
+      ],
+      "args": [
+        "-y",
+        "server-github"
+      ],
+      "command": "npx"
+    },
+    "legacy": {
+      "command": "legacy-server",
+      "disabled": true
+    }
+  }
+}
+`,
+		},
+		{
+			agent: &Copilot{},
+			want: `{
+  "servers": {
+    "docs": {
+      "headers": {
+        "Authorization": "Bearer ${TOKEN}"
+      },
+      "type": "http",
+      "url": "https://example.com/mcp"
+    },
+    "github": {
+      "args": [
+        "-y",
+        "server-github"
+      ],
+      "command": "npx",
+      "type": "stdio"
+    }
+  }
+}
+`,
+		},
+		{
+			agent: &Cursor{},
+			want: `{
+  "mcpServers": {
+    "docs": {
+      "headers": {
+        "Authorization": "Bearer ${TOKEN}"
+      },
+      "url": "https://example.com/mcp"
+    },
+    "github": {
+      "args": [
+        "-y",
+        "server-github"
+      ],
+      "command": "npx"
+    }
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.(Agent).ID(), func(t *testing.T) {
+			got, err := tt.agent.FormatMCPConfig(servers)
+			if err != nil {
+				t.Fatalf("FormatMCPConfig() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMCPConfig() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPConfigPath(t *testing.T) {
+	tests := []struct {
+		agent MCPConfigurator
+		want  string
+	}{
+		{&Claude{}, ".mcp.json"},
+		{&Roo{}, ".roo/mcp.json"},
+		{&Copilot{}, ".vscode/mcp.json"},
+		{&Cursor{}, ".cursor/mcp.json"},
+		{&Cline{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.agent.MCPConfigPath(false); got != tt.want {
+			t.Errorf("%T.MCPConfigPath(false) = %q, want %q", tt.agent, got, tt.want)
+		}
+	}
+}
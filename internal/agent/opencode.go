@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// OpenCode implements the OpenCode-specific conversion logic
+type OpenCode struct{}
+
+// ID returns the unique identifier for OpenCode agent
+func (o *OpenCode) ID() string {
+	return "opencode"
+}
+
+// Name returns the display name for OpenCode agent
+func (o *OpenCode) Name() string {
+	return "OpenCode"
+}
+
+// FormatFile converts a path to OpenCode's @file reference format
+func (o *OpenCode) FormatFile(path string) string {
+	return "@" + path
+}
+
+// FormatMCP formats an MCP command for OpenCode agent
+func (o *OpenCode) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef formats an OpenCode command invocation
+func (o *OpenCode) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef formats an @-mention of an OpenCode agent, named after its file
+func (o *OpenCode) FormatModeRef(mode model.Mode) (string, error) {
+	return "@" + SourceName(mode.Path), nil
+}
+
+// FormatMemory processes memory contexts for OpenCode agent
+func (o *OpenCode) FormatMemory(memories []model.Memory) (string, error) {
+	// AGENTS.md is plain markdown; return the content as is
+	return joinMemories(memories), nil
+}
+
+// OpenCodeCommandMeta is the frontmatter of an OpenCode command file
+type OpenCodeCommandMeta struct {
+	Description string `yaml:"description,omitempty"`
+	Agent       string `yaml:"agent,omitempty"`
+	Model       string `yaml:"model,omitempty"`
+}
+
+// FormatCommand processes command definitions for OpenCode agent
+func (o *OpenCode) FormatCommand(commands []model.Command) (string, error) {
+	var outputs []string
+	for _, cmd := range commands {
+		var meta OpenCodeCommandMeta
+		// Populate from opencode section. Ignore error if section is missing.
+		_ = cmd.UnmarshalSection("opencode", &meta)
+		warnToolsIgnored(o.ID(), cmd.Path, cmd.Raw)
+
+		// Priority: opencode.description > top-level cmd.Description
+		if meta.Description == "" {
+			meta.Description = cmd.Description
+		}
+
+		if meta.Description == "" && meta.Agent == "" && meta.Model == "" {
+			outputs = append(outputs, cmd.Content)
+			continue
+		}
+		yamlWithFences, err := frontmatter.Wrap(meta)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal opencode command frontmatter: %w", err)
+		}
+		outputs = append(outputs, yamlWithFences+cmd.Content)
+	}
+	return strings.Join(outputs, "\n\n"), nil
+}
+
+// MemoryPath returns the default path for OpenCode agent memory files
+func (o *OpenCode) MemoryPath(userScope bool) string {
+	if userScope {
+		return ".config/opencode/AGENTS.md"
+	}
+	return "AGENTS.md"
+}
+
+// CommandPath returns the default path for OpenCode agent command files
+func (o *OpenCode) CommandPath(userScope bool) string {
+	if userScope {
+		return ".config/opencode/command/"
+	}
+	return ".opencode/command/"
+}
+
+// OpenCodeAgentMeta is the frontmatter of an OpenCode agent file.
+// Tools and permission keep the user-provided structure as-is.
+type OpenCodeAgentMeta struct {
+	Description string `yaml:"description"`
+	Mode        string `yaml:"mode,omitempty"`
+	Model       string `yaml:"model,omitempty"`
+	Tools       any    `yaml:"tools,omitempty"`
+	Permission  any    `yaml:"permission,omitempty"`
+}
+
+// FormatMode processes mode definitions for OpenCode agent (agent files)
+// OpenCode does not support combining multiple agents in one file.
+func (o *OpenCode) FormatMode(modes []model.Mode) (string, error) {
+	// No modes: return empty string without error
+	if len(modes) == 0 {
+		return "", nil
+	}
+	// Multiple modes are not supported
+	if len(modes) > 1 {
+		return "", fmt.Errorf("opencode agent does not support multiple modes")
+	}
+
+	mode := modes[0]
+
+	var meta OpenCodeAgentMeta
+	// Unmarshal section if present
+	if mode.Raw != nil {
+		_ = mode.UnmarshalSection("opencode", &meta)
+	}
+
+	// Fallback to common description when empty
+	if meta.Description == "" {
+		meta.Description = mode.Description
+	}
+	if meta.Description == "" {
+		return "", fmt.Errorf("opencode agent requires a description: provide either top-level 'description' or 'opencode.description'")
+	}
+	// Fallback to the neutral tools for settings not provided under opencode
+	if meta.Tools == nil || meta.Permission == nil {
+		spec, err := parseToolSpec(mode.Path, mode.Raw)
+		if err != nil {
+			return "", fmt.Errorf("opencode agent %s: %w", mode.Path, err)
+		}
+		tools, permission := openCodeTools(mode.Path, spec)
+		if meta.Tools == nil && len(tools) > 0 {
+			meta.Tools = tools
+		}
+		if meta.Permission == nil && len(permission) > 0 {
+			meta.Permission = permission
+		}
+	}
+	switch meta.Mode {
+	case "", "primary", "subagent", "all":
+	default:
+		return "", fmt.Errorf("unsupported opencode agent mode %q: must be one of primary, subagent, all", meta.Mode)
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(meta)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal opencode agent frontmatter: %w", err)
+	}
+	return yamlWithFences + strings.TrimLeft(mode.Content, "\n"), nil
+}
+
+// openCodeToolNames maps neutral tools to OpenCode tools
+var openCodeToolNames = map[string][]string{
+	ToolRead:    {"read"},
+	ToolSearch:  {"grep", "glob", "list"},
+	ToolEdit:    {"edit", "write", "patch"},
+	ToolCommand: {"bash"},
+	ToolWeb:     {"webfetch"},
+}
+
+// openCodePermissionKeys maps neutral tools to the keys of the OpenCode permission setting
+var openCodePermissionKeys = map[string]string{
+	ToolEdit:    "edit",
+	ToolCommand: "bash",
+	ToolWeb:     "webfetch",
+}
+
+// FormatToolRef formats the OpenCode tools of a neutral tool as code spans
+func (o *OpenCode) FormatToolRef(tool string) (string, error) {
+	return formatToolRef(o.ID(), tool, openCodeToolNames, codeSpan)
+}
+
+// openCodeTools translates neutral tools to the OpenCode tools and permission settings.
+// Tools are enabled or disabled as a whole, while permissions, including command patterns,
+// are only available for edit, bash, and webfetch.
+func openCodeTools(source string, spec ToolSpec) (tools, permission yaml.MapSlice) {
+	for _, tool := range neutralTools {
+		action := spec.action(tool)
+		names, ok := openCodeToolNames[tool]
+		if !ok {
+			if spec.listed(tool) || len(spec.Permissions[tool]) > 0 {
+				warnTool("opencode", source, tool, "the tool cannot be expressed and is ignored")
+			}
+			continue
+		}
+		// A tools list disables the tools it does not name
+		if len(spec.Tools) > 0 || action == PermissionDeny {
+			for _, name := range names {
+				tools = append(tools, yaml.MapItem{Key: name, Value: spec.enabled(tool)})
+			}
+		}
+
+		key, ok := openCodePermissionKeys[tool]
+		if !ok {
+			if action == PermissionAsk {
+				warnTool("opencode", source, tool, "ask permissions cannot be expressed and are ignored")
+			}
+			continue
+		}
+		patterns := spec.commandPatterns()
+		if tool != ToolCommand || len(patterns) == 0 {
+			if action != "" {
+				permission = append(permission, yaml.MapItem{Key: key, Value: action})
+			}
+			continue
+		}
+		// The catch-all rule comes first so that the command patterns refine it
+		rules := yaml.MapSlice{}
+		if action != "" {
+			rules = append(rules, yaml.MapItem{Key: allCommands, Value: action})
+		}
+		for _, pattern := range patterns {
+			rules = append(rules, yaml.MapItem{Key: pattern, Value: spec.Permissions[ToolCommand][pattern]})
+		}
+		permission = append(permission, yaml.MapItem{Key: key, Value: rules})
+	}
+	return tools, permission
+}
+
+// ModePath returns the default path for OpenCode agent mode (agent) files
+func (o *OpenCode) ModePath(userScope bool) string {
+	if userScope {
+		return ".config/opencode/agent/"
+	}
+	return ".opencode/agent/"
+}
@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestOpenCode_ID_Name(t *testing.T) {
+	o := &OpenCode{}
+	if o.ID() != "opencode" {
+		t.Errorf("expected ID 'opencode', got %q", o.ID())
+	}
+	if o.Name() != "OpenCode" {
+		t.Errorf("expected Name 'OpenCode', got %q", o.Name())
+	}
+}
+
+func TestOpenCode_FormatMode(t *testing.T) {
+	o := &OpenCode{}
+
+	mode := model.Mode{
+		Description: "Top-level description",
+		Raw: map[string]any{
+			"opencode": map[string]any{
+				"description": "Reviews code",
+				"mode":        "subagent",
+				"model":       "anthropic/claude-sonnet-4",
+				"tools": map[string]any{
+					"write": false,
+					"edit":  false,
+				},
+				"permission": map[string]any{
+					"bash": map[string]any{"git diff": "allow", "*": "ask"},
+				},
+			},
+		},
+		Content: "\nYou review code.",
+	}
+	got, err := o.FormatMode([]model.Mode{mode})
+	if err != nil {
+		t.Fatalf("FormatMode() error = %v", err)
+	}
+	for _, want := range []string{
+		"description: Reviews code\n",
+		"mode: subagent\n",
+		"model: anthropic/claude-sonnet-4\n",
+		"tools:\n  edit: false\n  write: false\n",
+		"permission:\n  bash:\n",
+		"git diff: allow",
+		"---\n\nYou review code.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatMode() output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestOpenCode_FormatMode_Errors(t *testing.T) {
+	o := &OpenCode{}
+	if _, err := o.FormatMode([]model.Mode{{Content: "x", Raw: map[string]any{}}}); err == nil {
+		t.Errorf("expected error for missing description")
+	}
+	bad := model.Mode{Description: "d", Raw: map[string]any{"opencode": map[string]any{"mode": "helper"}}}
+	if _, err := o.FormatMode([]model.Mode{bad}); err == nil {
+		t.Errorf("expected error for unsupported mode")
+	}
+	if _, err := o.FormatMode([]model.Mode{{Description: "a"}, {Description: "b"}}); err == nil {
+		t.Errorf("expected error for multiple modes")
+	}
+}
+
+func TestOpenCode_FormatCommand(t *testing.T) {
+	o := &OpenCode{}
+	got, err := o.FormatCommand([]model.Command{{
+		Description: "Run tests",
+		Content:     "Run the full test suite.",
+		Raw: map[string]any{
+			"opencode": map[string]any{"agent": "build", "model": "anthropic/claude-haiku-4"},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	want := "---\ndescription: Run tests\nagent: build\nmodel: anthropic/claude-haiku-4\n---\n\nRun the full test suite."
+	if got != want {
+		t.Errorf("FormatCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// plainMarkdown provides the shared behavior of agents that read plain markdown
+// rules and prompts without agent-specific syntax. Agents embed it and only
+// implement their identity, default paths, commands, and any format that differs.
+type plainMarkdown struct{}
+
+// FormatFile converts a path to a plain code span reference
+func (plainMarkdown) FormatFile(path string) string {
+	return fmt.Sprintf("`%s`", path)
+}
+
+// FormatMCP formats an MCP command
+func (plainMarkdown) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef formats a slash command invocation named after the command's source file
+func (plainMarkdown) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef rejects mode references, which plain markdown agents do not support
+func (plainMarkdown) FormatModeRef(mode model.Mode) (string, error) {
+	return "", fmt.Errorf("modes are not supported by this agent")
+}
+
+// FormatMemory returns the memory contents as is
+func (plainMarkdown) FormatMemory(memories []model.Memory) (string, error) {
+	return joinMemories(memories), nil
+}
+
+// formatPlainCommands joins the command bodies of a plain markdown agent; agent-specific fields are not used
+func formatPlainCommands(agentName string, commands []model.Command) string {
+	contents := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		warnToolsIgnored(agentName, cmd.Path, cmd.Raw)
+		contents = append(contents, cmd.Content)
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+// FormatMode rejects modes, which plain markdown agents do not support
+func (plainMarkdown) FormatMode(modes []model.Mode) (string, error) {
+	return "", fmt.Errorf("modes are not supported by this agent")
+}
+
+// ModePath returns an empty path as modes are not supported
+func (plainMarkdown) ModePath(userScope bool) string {
+	return ""
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/log"
+	"github.com/uphy/agent-sync/internal/model"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPlainMarkdownAgents_Paths(t *testing.T) {
+	tests := []struct {
+		agent   Agent
+		id      string
+		name    string
+		memory  [2]string // project, user
+		command [2]string // project, user
+	}{
+		{
+			agent:   &Junie{},
+			id:      "junie",
+			name:    "Junie",
+			memory:  [2]string{".junie/guidelines.md", ".junie/guidelines.md"},
+			command: [2]string{"", ""},
+		},
+		{
+			agent:   &AmazonQ{},
+			id:      "amazonq",
+			name:    "Amazon Q",
+			memory:  [2]string{".amazonq/rules/", ".amazonq/rules/"},
+			command: [2]string{".amazonq/prompts/", ".aws/amazonq/prompts/"},
+		},
+		{
+			agent:   &Continue{},
+			id:      "continue",
+			name:    "Continue",
+			memory:  [2]string{".continue/rules/", ".continue/rules/"},
+			command: [2]string{".continue/prompts/", ".continue/prompts/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			a := tt.agent
+			if a.ID() != tt.id {
+				t.Errorf("ID() = %q, want %q", a.ID(), tt.id)
+			}
+			if a.Name() != tt.name {
+				t.Errorf("Name() = %q, want %q", a.Name(), tt.name)
+			}
+			if got := a.MemoryPath(false); got != tt.memory[0] {
+				t.Errorf("MemoryPath(false) = %q, want %q", got, tt.memory[0])
+			}
+			if got := a.MemoryPath(true); got != tt.memory[1] {
+				t.Errorf("MemoryPath(true) = %q, want %q", got, tt.memory[1])
+			}
+			if got := a.CommandPath(false); got != tt.command[0] {
+				t.Errorf("CommandPath(false) = %q, want %q", got, tt.command[0])
+			}
+			if got := a.CommandPath(true); got != tt.command[1] {
+				t.Errorf("CommandPath(true) = %q, want %q", got, tt.command[1])
+			}
+			if got := a.FormatFile("src/main.go"); got != "`src/main.go`" {
+				t.Errorf("FormatFile() = %q", got)
+			}
+			if _, err := a.FormatMode([]model.Mode{{Content: "x"}}); err == nil {
+				t.Errorf("FormatMode() expected error")
+			}
+		})
+	}
+}
+
+func TestJunie_FormatCommand(t *testing.T) {
+	j := &Junie{}
+	if _, err := j.FormatCommand([]model.Command{{Content: "x"}}); err == nil {
+		t.Errorf("expected error for unsupported commands")
+	}
+}
+
+func TestAmazonQ_FormatCommand(t *testing.T) {
+	a := &AmazonQ{}
+	got, err := a.FormatCommand([]model.Command{{Description: "ignored", Content: "# Prompt"}})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	if got != "# Prompt" {
+		t.Errorf("FormatCommand() = %q, want %q", got, "# Prompt")
+	}
+}
+
+func TestAmazonQ_FormatCommandWarnsWithAgent(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	previous := log.GetLogger()
+	log.SetGlobalLogger(zap.New(core))
+	defer log.SetGlobalLogger(previous)
+
+	a := &AmazonQ{}
+	cmd := model.Command{Path: "cmds/a.md", Content: "x", Raw: map[string]any{"tools": []any{"read"}}}
+	if _, err := a.FormatCommand([]model.Command{cmd}); err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].ContextMap()["agent"] != "amazonq" {
+		t.Errorf("expected a warning naming the agent, got %v", entries)
+	}
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestFormatCommandRef(t *testing.T) {
+	command := model.Command{
+		Path: "/cfg/commands/deploy.md",
+		Raw:  map[string]any{"continue": map[string]any{"name": "ship"}},
+	}
+
+	tests := []struct {
+		agent   Agent
+		want    string
+		wantErr bool
+	}{
+		{agent: &Claude{}, want: "/deploy"},
+		{agent: &Roo{}, want: "/deploy"},
+		{agent: &Cline{}, want: "/deploy.md"},
+		{agent: &Codex{}, want: "/prompts:deploy"},
+		{agent: &Copilot{}, want: "/deploy"},
+		{agent: &Cursor{}, want: "/deploy"},
+		{agent: &Gemini{}, want: "/deploy"},
+		{agent: &Windsurf{}, want: "/deploy"},
+		{agent: &OpenCode{}, want: "/deploy"},
+		{agent: &AmazonQ{}, want: "@deploy"},
+		{agent: &Continue{}, want: "/ship"},
+		{agent: &Junie{}, wantErr: true},
+		{agent: &Kiro{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.ID(), func(t *testing.T) {
+			got, err := tt.agent.FormatCommandRef(command)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatCommandRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatModeRef(t *testing.T) {
+	mode := model.Mode{
+		Path: "/cfg/modes/reviewer.md",
+		Raw: map[string]any{
+			"claude": map[string]any{"name": "code-reviewer"},
+			"roo":    map[string]any{"slug": "review"},
+		},
+	}
+
+	tests := []struct {
+		agent   Agent
+		want    string
+		wantErr bool
+	}{
+		{agent: &Claude{}, want: "@agent-code-reviewer"},
+		{agent: &Roo{}, want: "`review`"},
+		{agent: &Copilot{}, want: "`reviewer`"},
+		{agent: &OpenCode{}, want: "@reviewer"},
+		{agent: &Cline{}, wantErr: true},
+		{agent: &Codex{}, wantErr: true},
+		{agent: &Cursor{}, wantErr: true},
+		{agent: &Gemini{}, wantErr: true},
+		{agent: &Windsurf{}, wantErr: true},
+		{agent: &Kiro{}, wantErr: true},
+		{agent: &AmazonQ{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.ID(), func(t *testing.T) {
+			got, err := tt.agent.FormatModeRef(mode)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatModeRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Modes without the agent-specific name cannot be referenced
+	if _, err := (&Claude{}).FormatModeRef(model.Mode{Path: "/cfg/modes/x.md"}); err == nil {
+		t.Error("expected an error for a Claude subagent without a name")
+	}
+}
@@ -21,6 +21,15 @@ func (r *Registry) RegisterDefaults() {
 	r.Register(&Claude{})
 	r.Register(&Cline{})
 	r.Register(&Copilot{}) // Register Copilot agent
+	r.Register(&Cursor{})
+	r.Register(&Gemini{})
+	r.Register(&Codex{})
+	r.Register(&Windsurf{})
+	r.Register(&Kiro{})
+	r.Register(&Junie{})
+	r.Register(&AmazonQ{})
+	r.Register(&Continue{})
+	r.Register(&OpenCode{})
 }
 
 // Register registers an agent
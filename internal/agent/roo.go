@@ -3,7 +3,6 @@ package agent
 import (
 	"fmt"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/uphy/agent-sync/internal/frontmatter"
@@ -36,12 +35,31 @@ func (r *Roo) FormatMCP(agent, command string, args ...string) string {
 	return formatMCP(agent, command, args...)
 }
 
-// FormatMemory processes a memory context for Roo agent
-func (r *Roo) FormatMemory(content string) (string, error) {
+// FormatCommandRef formats a Roo slash command invocation
+func (r *Roo) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef formats a Roo mode reference; Roo modes are switched to by slug
+func (r *Roo) FormatModeRef(mode model.Mode) (string, error) {
+	var rm struct {
+		Slug string `yaml:"slug"`
+	}
+	if err := mode.UnmarshalSection("roo", &rm); err != nil {
+		return "", err
+	}
+	if rm.Slug == "" {
+		return "", fmt.Errorf("mode missing required field 'slug'")
+	}
+	return fmt.Sprintf("`%s`", rm.Slug), nil
+}
+
+// FormatMemory processes memory contexts for Roo agent
+func (r *Roo) FormatMemory(memories []model.Memory) (string, error) {
 	// Process the memory context for Roo
 	// For now, we're simply returning the content as is
 	// Any Roo-specific formatting rules could be applied here
-	return content, nil
+	return joinMemories(memories), nil
 }
 
 // RooSlashMeta is a simple struct for Roo-specific metadata.
@@ -59,6 +77,7 @@ func (r *Roo) FormatCommand(commands []model.Command) (string, error) {
 		var meta RooSlashMeta
 		// Populate from roo section. Ignore error if section is missing.
 		_ = cmd.UnmarshalSection("roo", &meta) // roo: argument-hint
+		warnToolsIgnored(r.ID(), cmd.Path, cmd.Raw)
 
 		body := strings.TrimLeft(cmd.Content, "\n")
 
@@ -138,6 +157,16 @@ func (r *Roo) FormatMode(modes []model.Mode) (string, error) {
 		if rm.RoleDefinition == "" {
 			return "", fmt.Errorf("mode at index %d missing required field 'roleDefinition'", i)
 		}
+		// Fallback to the neutral tools when roo.groups is not provided
+		if rm.Groups == nil {
+			spec, err := parseToolSpec(m.Path, m.Raw)
+			if err != nil {
+				return "", fmt.Errorf("mode at index %d: %w", i, err)
+			}
+			if groups := translateToolList(r.ID(), m.Path, spec, rooToolGroups); len(groups) > 0 {
+				rm.Groups = groups
+			}
+		}
 		// Ensure non-nil groups (preserve user structure)
 		if rm.Groups == nil {
 			rm.Groups = []string{}
@@ -161,6 +190,31 @@ func (r *Roo) FormatMode(modes []model.Mode) (string, error) {
 	return yml, nil
 }
 
+// rooToolGroups maps neutral tools to Roo mode tool groups
+var rooToolGroups = map[string][]string{
+	ToolRead:    {"read"},
+	ToolSearch:  {"read"},
+	ToolEdit:    {"edit"},
+	ToolCommand: {"command"},
+	ToolWeb:     {"browser"},
+	ToolMCP:     {"mcp"},
+}
+
+// rooToolNames maps neutral tools to Roo tools
+var rooToolNames = map[string][]string{
+	ToolRead:    {"read_file"},
+	ToolSearch:  {"search_files", "list_files"},
+	ToolEdit:    {"apply_diff", "write_to_file"},
+	ToolCommand: {"execute_command"},
+	ToolWeb:     {"browser_action"},
+	ToolMCP:     {"use_mcp_tool"},
+}
+
+// FormatToolRef formats the Roo tools of a neutral tool as code spans
+func (r *Roo) FormatToolRef(tool string) (string, error) {
+	return formatToolRef(r.ID(), tool, rooToolNames, codeSpan)
+}
+
 // ModePath returns the default path for Roo agent mode files
 // Project scope aggregates into a single file ".roomodes"
 // User scope aggregates into VS Code globalStorage custom_modes.yaml under the user's home directory
@@ -169,22 +223,50 @@ func (r *Roo) ModePath(userScope bool) string {
 		// Project scope: single aggregated file in project root
 		return ".roomodes"
 	}
-	// User scope: platform-specific VS Code globalStorage path (relative to home)
-	// macOS: ~/Library/Application Support/Code/User/globalStorage/rooveterinaryinc.roo-cline/settings/custom_modes.yaml
-	// Linux: ~/.config/Code/User/globalStorage/rooveterinaryinc.roo-cline/settings/custom_modes.yaml
-	// Windows: %APPDATA%\Code\User\globalStorage\rooveterinaryinc.roo-cline\settings\custom_modes.yaml
-	switch os := runtime.GOOS; os {
-	case "darwin":
-		return filepath.Join("Library", "Application Support", "Code", "User", "globalStorage", "rooveterinaryinc.roo-cline", "settings", "custom_modes.yaml")
-	case "linux":
-		return filepath.Join(".config", "Code", "User", "globalStorage", "rooveterinaryinc.roo-cline", "settings", "custom_modes.yaml")
-	case "windows":
-		// Return a relative path under APPDATA; pipeline will resolve against home for user scope
-		return filepath.Join("AppData", "Roaming", "Code", "User", "globalStorage", "rooveterinaryinc.roo-cline", "settings", "custom_modes.yaml")
-	default:
-		// Fallback to Linux-like path
-		return filepath.Join(".config", "Code", "User", "globalStorage", "rooveterinaryinc.roo-cline", "settings", "custom_modes.yaml")
+	// User scope: VS Code globalStorage path (relative to home)
+	return vscodeUserPath("globalStorage", "rooveterinaryinc.roo-cline", "settings", "custom_modes.yaml")
+}
+
+// MergeKeys returns the keyed list of Roo mode files: customModes entries identified by slug
+func (r *Roo) MergeKeys(taskType string) (string, string, bool) {
+	if taskType == "mode" {
+		return "customModes", "slug", true
+	}
+	return "", "", false
+}
+
+// FormatMCPConfig renders the Roo MCP configuration (mcp.json / mcp_settings.json)
+func (r *Roo) FormatMCPConfig(servers []model.MCPServer) (string, error) {
+	return renderMCPConfig(r.ID(), "mcpServers", servers, mcpEntryOptions{
+		types: map[string]string{
+			model.MCPTypeHTTP: "streamable-http",
+			model.MCPTypeSSE:  "sse",
+		},
+		disabledKey: "disabled",
+	})
+}
+
+// MCPConfigPath returns the default path for the Roo MCP configuration
+// Project scope uses ".roo/mcp.json"; user scope uses mcp_settings.json in VS Code globalStorage
+func (r *Roo) MCPConfigPath(userScope bool) string {
+	if !userScope {
+		return ".roo/mcp.json"
+	}
+	return vscodeUserPath("globalStorage", "rooveterinaryinc.roo-cline", "settings", "mcp_settings.json")
+}
+
+// FormatIgnore renders the Roo ignore file (.rooignore)
+func (r *Roo) FormatIgnore(patterns []string) (string, error) {
+	return formatIgnoreFile(patterns), nil
+}
+
+// IgnorePath returns the default path for the Roo ignore file
+// The ignore file is only read from the workspace root, so there is no user-scope default.
+func (r *Roo) IgnorePath(userScope bool) string {
+	if userScope {
+		return ""
 	}
+	return ".rooignore"
 }
 
 // Legacy compatibility for slash commands removed: only top-level 'description' and 'roo.argument-hint' are supported.
@@ -0,0 +1,249 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/log"
+	"go.uber.org/zap"
+)
+
+// Neutral tools of the top-level 'tools' and 'permissions' frontmatter keys of modes and commands
+const (
+	ToolRead    = "read"    // read files
+	ToolSearch  = "search"  // search file names and contents
+	ToolEdit    = "edit"    // create and modify files
+	ToolCommand = "command" // run shell commands
+	ToolWeb     = "web"     // fetch web pages and search the web
+	ToolMCP     = "mcp"     // use the tools of MCP servers
+)
+
+// neutralTools lists the neutral tools in the order agents translate them
+var neutralTools = []string{ToolRead, ToolSearch, ToolEdit, ToolCommand, ToolWeb, ToolMCP}
+
+// Actions of the neutral 'permissions' frontmatter key
+const (
+	PermissionAllow = "allow"
+	PermissionAsk   = "ask"
+	PermissionDeny  = "deny"
+)
+
+// allCommands is the command pattern of a permission given for a tool as a whole
+const allCommands = "*"
+
+// ToolSpec is the neutral tool and permission vocabulary of a mode or command
+type ToolSpec struct {
+	// Tools lists the neutral tools the mode or command uses
+	Tools []string
+	// Permissions maps neutral tools to their rules: the action per command pattern.
+	// A permission given as a single action applies to all commands (pattern "*").
+	Permissions map[string]map[string]string
+}
+
+// parseToolSpec reads the neutral 'tools' and 'permissions' keys of the frontmatter of source.
+// Tool names outside the neutral vocabulary are ignored with a warning, so that sources
+// written before the vocabulary existed keep working.
+func parseToolSpec(source string, fm map[string]any) (ToolSpec, error) {
+	spec := ToolSpec{Permissions: map[string]map[string]string{}}
+
+	switch tools := fm["tools"].(type) {
+	case nil:
+	case []any:
+		for _, item := range tools {
+			tool, ok := item.(string)
+			if !ok {
+				return ToolSpec{}, fmt.Errorf("tools must contain only strings, got %T", item)
+			}
+			if !isNeutralTool(tool) {
+				warnUnknownTool(source, "tools", tool)
+				continue
+			}
+			spec.Tools = append(spec.Tools, tool)
+		}
+	default:
+		return ToolSpec{}, fmt.Errorf("tools must be a list of tools, got %T", tools)
+	}
+
+	switch permissions := fm["permissions"].(type) {
+	case nil:
+	case map[string]any:
+		for tool, v := range permissions {
+			if !isNeutralTool(tool) {
+				warnUnknownTool(source, "permissions", tool)
+				continue
+			}
+			rules := map[string]string{}
+			switch rule := v.(type) {
+			case string:
+				rules[allCommands] = rule
+			case map[string]any:
+				if tool != ToolCommand {
+					return ToolSpec{}, fmt.Errorf("permissions.%s must be an action: only %s permissions take command patterns", tool, ToolCommand)
+				}
+				for pattern, action := range rule {
+					s, ok := action.(string)
+					if !ok {
+						return ToolSpec{}, fmt.Errorf("permissions.%s.%s must be an action, got %T", tool, pattern, action)
+					}
+					rules[pattern] = s
+				}
+			default:
+				return ToolSpec{}, fmt.Errorf("permissions.%s must be an action or a mapping of command patterns to actions, got %T", tool, v)
+			}
+			for pattern, action := range rules {
+				switch action {
+				case PermissionAllow, PermissionAsk, PermissionDeny:
+				default:
+					return ToolSpec{}, fmt.Errorf("unsupported action %q for permissions.%s (pattern %q): must be one of allow, ask, deny", action, tool, pattern)
+				}
+			}
+			spec.Permissions[tool] = rules
+		}
+	default:
+		return ToolSpec{}, fmt.Errorf("permissions must be a mapping of tools to actions, got %T", permissions)
+	}
+
+	return spec, nil
+}
+
+// isNeutralTool reports whether tool is part of the neutral vocabulary
+func isNeutralTool(tool string) bool {
+	for _, t := range neutralTools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// warnUnknownTool warns that a tool name outside the neutral vocabulary is ignored
+func warnUnknownTool(source, field, tool string) {
+	log.Warn("Unknown neutral tool is ignored: must be one of "+strings.Join(neutralTools, ", "),
+		zap.String("source", source), zap.String("field", field), zap.String("tool", tool))
+}
+
+// IsEmpty reports whether the spec has neither tools nor permissions
+func (s ToolSpec) IsEmpty() bool {
+	return len(s.Tools) == 0 && len(s.Permissions) == 0
+}
+
+// listed reports whether tool is in the tools list
+func (s ToolSpec) listed(tool string) bool {
+	for _, t := range s.Tools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// action returns the action for a tool as a whole, or "" when there is none
+func (s ToolSpec) action(tool string) string {
+	return s.Permissions[tool][allCommands]
+}
+
+// enabled reports whether a tool is available: it is listed or allowed, and not denied
+func (s ToolSpec) enabled(tool string) bool {
+	switch s.action(tool) {
+	case PermissionDeny:
+		return false
+	case PermissionAllow:
+		return true
+	default:
+		return s.listed(tool)
+	}
+}
+
+// commandPatterns returns the command patterns with their own rules, sorted
+func (s ToolSpec) commandPatterns() []string {
+	var patterns []string
+	for pattern := range s.Permissions[ToolCommand] {
+		if pattern != allCommands {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// allowsCommandPattern reports whether any command pattern is allowed
+func (s ToolSpec) allowsCommandPattern() bool {
+	for _, pattern := range s.commandPatterns() {
+		if s.Permissions[ToolCommand][pattern] == PermissionAllow {
+			return true
+		}
+	}
+	return false
+}
+
+// translateToolList maps the enabled neutral tools to agent tool names, in vocabulary order
+// and without duplicates. Tools without a mapping, 'ask' actions, and command patterns
+// cannot be expressed as a plain tool list and are warned about.
+func translateToolList(agentName, source string, spec ToolSpec, names map[string][]string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, tool := range neutralTools {
+		if spec.action(tool) == PermissionAsk {
+			warnTool(agentName, source, tool, "ask permissions cannot be expressed and are ignored")
+		}
+		if !spec.enabled(tool) {
+			continue
+		}
+		mapped, ok := names[tool]
+		if !ok {
+			warnTool(agentName, source, tool, "the tool cannot be expressed and is ignored")
+			continue
+		}
+		for _, name := range mapped {
+			if !seen[name] {
+				seen[name] = true
+				out = append(out, name)
+			}
+		}
+	}
+	if len(spec.commandPatterns()) > 0 {
+		warnTool(agentName, source, ToolCommand, "command pattern permissions cannot be expressed and are ignored")
+	}
+	return out
+}
+
+// formatToolRef formats the agent tools of a neutral tool as a comma-separated list, each tool name
+// written with format
+func formatToolRef(agentName, tool string, names map[string][]string, format func(name string) string) (string, error) {
+	if !isNeutralTool(tool) {
+		return "", fmt.Errorf("unknown tool %q in tool reference: must be one of %s", tool, strings.Join(neutralTools, ", "))
+	}
+	mapped, ok := names[tool]
+	if !ok {
+		return "", fmt.Errorf("%s agent has no tool for %q", agentName, tool)
+	}
+	refs := make([]string, len(mapped))
+	for i, name := range mapped {
+		refs[i] = format(name)
+	}
+	return strings.Join(refs, ", "), nil
+}
+
+// codeSpan formats a tool name as a markdown code span
+func codeSpan(name string) string {
+	return "`" + name + "`"
+}
+
+// warnTool warns that a neutral tool or permission cannot be expressed for an agent
+func warnTool(agentName, source, tool, reason string) {
+	log.Warn("Neutral tool is not fully supported by agent: "+reason,
+		zap.String("agent", agentName), zap.String("source", source), zap.String("tool", tool))
+}
+
+// warnToolsIgnored warns when a mode or command uses the neutral tool vocabulary
+// for an agent whose files have no tool settings
+func warnToolsIgnored(agentName, source string, fm map[string]any) {
+	_, hasTools := fm["tools"]
+	_, hasPermissions := fm["permissions"]
+	if !hasTools && !hasPermissions {
+		return
+	}
+	log.Warn("Neutral tools and permissions cannot be expressed by agent and are ignored",
+		zap.String("agent", agentName), zap.String("source", source))
+}
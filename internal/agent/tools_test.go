@@ -0,0 +1,248 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestParseToolSpec_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fm   map[string]any
+		want string
+	}{
+		{name: "tools not a list", fm: map[string]any{"tools": "read"}, want: "tools must be a list"},
+		{name: "invalid action", fm: map[string]any{"permissions": map[string]any{"edit": "never"}}, want: `unsupported action "never"`},
+		{name: "patterns for non-command", fm: map[string]any{"permissions": map[string]any{"edit": map[string]any{"*.go": "allow"}}}, want: "only command permissions take command patterns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseToolSpec("mode.md", tt.fm)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseToolSpec() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseToolSpec_UnknownTools(t *testing.T) {
+	// Names outside the vocabulary, such as agent-specific tool names, are ignored
+	fm := map[string]any{
+		"tools":       []any{"Read", "read"},
+		"permissions": map[string]any{"Bash": "deny", "edit": "deny"},
+	}
+	spec, err := parseToolSpec("mode.md", fm)
+	if err != nil {
+		t.Fatalf("parseToolSpec() error = %v", err)
+	}
+	if len(spec.Tools) != 1 || spec.Tools[0] != ToolRead {
+		t.Errorf("Tools = %v, want [read]", spec.Tools)
+	}
+	if _, ok := spec.Permissions["Bash"]; ok || len(spec.Permissions) != 1 {
+		t.Errorf("Permissions = %v, want only edit", spec.Permissions)
+	}
+}
+
+func TestClaudeTools(t *testing.T) {
+	tests := []struct {
+		name        string
+		fm          map[string]any
+		preapproved bool
+		wantAllowed string
+		wantDenied  string
+	}{
+		{
+			name:        "tools",
+			fm:          map[string]any{"tools": []any{"read", "search", "edit", "mcp"}},
+			wantAllowed: "Read, Grep, Glob, Edit, Write",
+		},
+		{
+			name: "deny for subagents",
+			fm: map[string]any{
+				"tools":       []any{"read", "command"},
+				"permissions": map[string]any{"web": "deny", "command": map[string]any{"git push *": "deny"}},
+			},
+			wantAllowed: "Read, Bash",
+			wantDenied:  "Bash(git push:*), WebFetch, WebSearch",
+		},
+		{
+			name: "subagent limited to command patterns",
+			fm: map[string]any{
+				"permissions": map[string]any{"command": map[string]any{"*": "deny", "git diff *": "allow", "make test": "allow"}},
+			},
+			wantAllowed: "Bash(git diff:*), Bash(make test)",
+		},
+		{
+			name: "pre-approved command patterns",
+			fm: map[string]any{
+				"tools":       []any{"read"},
+				"permissions": map[string]any{"edit": "ask", "command": map[string]any{"git add *": "allow", "git push *": "deny"}},
+			},
+			preapproved: true,
+			wantAllowed: "Read, Bash(git add:*)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parseToolSpec("mode.md", tt.fm)
+			if err != nil {
+				t.Fatalf("parseToolSpec() error = %v", err)
+			}
+			allowed, denied := claudeTools("test.md", spec, tt.preapproved)
+			if got := strings.Join(allowed, ", "); got != tt.wantAllowed {
+				t.Errorf("allowed = %q, want %q", got, tt.wantAllowed)
+			}
+			if got := strings.Join(denied, ", "); got != tt.wantDenied {
+				t.Errorf("denied = %q, want %q", got, tt.wantDenied)
+			}
+		})
+	}
+}
+
+func TestFormatMode_NeutralTools(t *testing.T) {
+	raw := map[string]any{
+		"description": "Reviews code",
+		"tools":       []any{"read", "search", "command"},
+		"permissions": map[string]any{
+			"edit":    "deny",
+			"command": map[string]any{"*": "ask", "git diff *": "allow"},
+		},
+		"claude": map[string]any{"name": "reviewer"},
+		"roo":    map[string]any{"slug": "reviewer", "name": "Reviewer", "roleDefinition": "Reviews code"},
+	}
+	mode := model.Mode{Description: "Reviews code", Raw: raw, Content: "Review.\n", Path: "/cfg/modes/reviewer.md"}
+
+	tests := []struct {
+		agent Agent
+		want  []string
+	}{
+		{agent: &Claude{}, want: []string{
+			"tools: Read, Grep, Glob, Bash, Bash(git diff:*)\n",
+			"disallowedTools: Edit, Write\n",
+		}},
+		{agent: &Roo{}, want: []string{"groups:\n      - read\n      - command\n"}},
+		{agent: &Copilot{}, want: []string{"tools:\n  - codebase\n  - search\n  - runCommands\n"}},
+		{agent: &OpenCode{}, want: []string{
+			"tools:\n  read: true\n  grep: true\n  glob: true\n  list: true\n  edit: false\n  write: false\n  patch: false\n  bash: true\n  webfetch: false\n",
+			"permission:\n  edit: deny\n  bash:\n    \"*\": ask\n    git diff *: allow\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.ID(), func(t *testing.T) {
+			got, err := tt.agent.FormatMode([]model.Mode{mode})
+			if err != nil {
+				t.Fatalf("FormatMode() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("FormatMode() =\n%s\nwant it to contain\n%s", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestClaude_FormatModeAgentTools verifies that the neutral keys are not read
+// when the claude section sets both tool lists
+func TestClaude_FormatModeAgentTools(t *testing.T) {
+	mode := model.Mode{
+		Description: "Reviews code",
+		Raw: map[string]any{
+			"tools":  "Read, Grep",
+			"claude": map[string]any{"name": "reviewer", "tools": "Read", "disallowedTools": "Edit"},
+		},
+		Content: "Review.\n",
+		Path:    "/cfg/modes/reviewer.md",
+	}
+	got, err := (&Claude{}).FormatMode([]model.Mode{mode})
+	if err != nil {
+		t.Fatalf("FormatMode() error = %v", err)
+	}
+	if !strings.Contains(got, "tools: Read\n") || !strings.Contains(got, "disallowedTools: Edit\n") {
+		t.Errorf("FormatMode() =\n%s\nwant the claude tool lists", got)
+	}
+}
+
+// TestFormat_AgentSectionOverridesNeutralTools verifies that agent-specific tool settings
+// take precedence over the neutral vocabulary.
+func TestFormat_AgentSectionOverridesNeutralTools(t *testing.T) {
+	cmd := model.Command{
+		Description: "Commit",
+		Raw: map[string]any{
+			"tools":   []any{"read", "edit"},
+			"claude":  map[string]any{"allowed-tools": "Bash(git commit:*)"},
+			"copilot": map[string]any{"tools": []any{"githubRepo"}},
+		},
+		Content: "Commit.",
+		Path:    "/cfg/commands/commit.md",
+	}
+
+	tests := []struct {
+		agent Agent
+		want  string
+	}{
+		{agent: &Claude{}, want: "allowed-tools: Bash(git commit:*)\n"},
+		{agent: &Copilot{}, want: "tools:\n  - githubRepo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.ID(), func(t *testing.T) {
+			got, err := tt.agent.FormatCommand([]model.Command{cmd})
+			if err != nil {
+				t.Fatalf("FormatCommand() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("FormatCommand() =\n%s\nwant it to contain\n%s", got, tt.want)
+			}
+		})
+	}
+
+	// Without the agent sections the neutral tools are translated
+	cmd.Raw = map[string]any{"tools": []any{"read", "edit"}}
+	got, err := (&Claude{}).FormatCommand([]model.Command{cmd})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	if !strings.Contains(got, "allowed-tools: Read, Edit, Write\n") {
+		t.Errorf("FormatCommand() =\n%s\nwant neutral tools translated", got)
+	}
+}
+
+func TestFormatToolRef(t *testing.T) {
+	tests := []struct {
+		agent   ToolFormatter
+		tool    string
+		want    string
+		wantErr bool
+	}{
+		{agent: &Claude{}, tool: ToolSearch, want: "`Grep`, `Glob`"},
+		{agent: &Roo{}, tool: ToolCommand, want: "`execute_command`"},
+		{agent: &Copilot{}, tool: ToolRead, want: "#codebase"},
+		{agent: &OpenCode{}, tool: ToolWeb, want: "`webfetch`"},
+		{agent: &Claude{}, tool: ToolMCP, wantErr: true},
+		{agent: &Claude{}, tool: "browse", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent.(Agent).ID()+"/"+tt.tool, func(t *testing.T) {
+			got, err := tt.agent.FormatToolRef(tt.tool)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatToolRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
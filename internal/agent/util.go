@@ -2,7 +2,12 @@ package agent
 
 import (
 	"fmt"
+	"path/filepath"
+	"runtime"
 	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/model"
 )
 
 func formatMCP(agent, command string, args ...string) string {
@@ -14,3 +19,85 @@ func formatMCP(agent, command string, args ...string) string {
 	}
 	return fmt.Sprintf("MCP tool `%s.%s%s`", agent, command, a)
 }
+
+// SourceName returns the name of a command or mode source file: its base name without the extension
+func SourceName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// vscodeUserPath returns a path under the VS Code user data directory, relative to the home directory.
+//
+//	macOS:   ~/Library/Application Support/Code/User
+//	Linux:   ~/.config/Code/User
+//	Windows: %APPDATA%\Code\User (resolved as AppData/Roaming under home)
+func vscodeUserPath(elem ...string) string {
+	var base string
+	switch runtime.GOOS {
+	case "darwin":
+		base = filepath.Join("Library", "Application Support", "Code", "User")
+	case "windows":
+		base = filepath.Join("AppData", "Roaming", "Code", "User")
+	default:
+		// Linux and fallback for other platforms
+		base = filepath.Join(".config", "Code", "User")
+	}
+	return filepath.Join(append([]string{base}, elem...)...)
+}
+
+// joinMemories concatenates the memory bodies with blank lines in between
+func joinMemories(memories []model.Memory) string {
+	contents := make([]string, 0, len(memories))
+	for _, m := range memories {
+		contents = append(contents, m.Content)
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+// unmarshalSection decodes fm[key] into out. A missing section leaves out untouched.
+// It is the counterpart of model.Command.UnmarshalSection for raw frontmatter maps.
+func unmarshalSection(fm map[string]any, key string, out any) error {
+	sec, ok := fm[key]
+	if !ok {
+		return nil
+	}
+	b, err := yaml.Marshal(sec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal section %q: %w", key, err)
+	}
+	if err := yaml.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("failed to unmarshal section %q: %w", key, err)
+	}
+	return nil
+}
+
+// joinList normalizes a frontmatter value written either as a single string or as a
+// list of strings into a comma-separated string. field is used in error messages.
+func joinList(field string, v any) (string, error) {
+	switch l := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return l, nil
+	case []any:
+		items := make([]string, 0, len(l))
+		for _, item := range l {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("%s must contain only strings, got %T", field, item)
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("%s must be a string or a list of strings, got %T", field, v)
+	}
+}
+
+// formatIgnoreFile renders patterns as a gitignore-style file
+func formatIgnoreFile(patterns []string) string {
+	if len(patterns) == 0 {
+		return ""
+	}
+	return strings.Join(patterns, "\n") + "\n"
+}
@@ -0,0 +1,190 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Maximum number of characters Windsurf reads from a single rule or workflow file.
+// Anything beyond them is silently truncated by Windsurf.
+const (
+	windsurfRuleMaxChars     = 6000
+	windsurfWorkflowMaxChars = 12000
+)
+
+// Windsurf rule activation triggers
+const (
+	windsurfTriggerAlwaysOn      = "always_on"
+	windsurfTriggerManual        = "manual"
+	windsurfTriggerModelDecision = "model_decision"
+	windsurfTriggerGlob          = "glob"
+)
+
+// Windsurf implements the Windsurf-specific conversion logic
+type Windsurf struct{}
+
+// ID returns the unique identifier for Windsurf agent
+func (w *Windsurf) ID() string {
+	return "windsurf"
+}
+
+// Name returns the display name for Windsurf agent
+func (w *Windsurf) Name() string {
+	return "Windsurf"
+}
+
+// FormatFile converts a path to Windsurf's @-mention file reference format
+func (w *Windsurf) FormatFile(path string) string {
+	return "@" + path
+}
+
+// FormatMCP formats an MCP command for Windsurf agent
+func (w *Windsurf) FormatMCP(agent, command string, args ...string) string {
+	return formatMCP(agent, command, args...)
+}
+
+// FormatCommandRef formats a Windsurf workflow invocation
+func (w *Windsurf) FormatCommandRef(command model.Command) (string, error) {
+	return "/" + SourceName(command.Path), nil
+}
+
+// FormatModeRef rejects mode references, as Windsurf does not support modes
+func (w *Windsurf) FormatModeRef(mode model.Mode) (string, error) {
+	return "", fmt.Errorf("windsurf agent does not support modes")
+}
+
+// windsurfRule is the frontmatter of a Windsurf rule file.
+// Globs may be written either as a single comma-separated string or as a list in the source.
+type windsurfRule struct {
+	Trigger     string `yaml:"trigger"`
+	Description string `yaml:"description,omitempty"`
+	Globs       any    `yaml:"globs,omitempty"`
+}
+
+// FormatMemory converts memory contexts into a Windsurf rule.
+// Activation metadata is read from the 'windsurf' section of the first memory's frontmatter.
+func (w *Windsurf) FormatMemory(memories []model.Memory) (string, error) {
+	if len(memories) == 0 {
+		return "", nil
+	}
+
+	var rule windsurfRule
+	if err := unmarshalSection(memories[0].Raw, "windsurf", &rule); err != nil {
+		return "", fmt.Errorf("windsurf frontmatter parse error: %w", err)
+	}
+	globs, err := joinList("windsurf.globs", rule.Globs)
+	if err != nil {
+		return "", err
+	}
+	if globs == "" {
+		rule.Globs = nil
+	} else {
+		rule.Globs = globs
+	}
+
+	switch rule.Trigger {
+	case "":
+		// Derive the trigger from the provided metadata
+		switch {
+		case globs != "":
+			rule.Trigger = windsurfTriggerGlob
+		case rule.Description != "":
+			rule.Trigger = windsurfTriggerModelDecision
+		default:
+			rule.Trigger = windsurfTriggerAlwaysOn
+		}
+	case windsurfTriggerAlwaysOn, windsurfTriggerManual, windsurfTriggerModelDecision:
+	case windsurfTriggerGlob:
+		if globs == "" {
+			return "", fmt.Errorf("windsurf rule with trigger %q requires 'windsurf.globs'", windsurfTriggerGlob)
+		}
+	default:
+		return "", fmt.Errorf("unsupported windsurf trigger %q: must be one of %s, %s, %s, %s",
+			rule.Trigger, windsurfTriggerAlwaysOn, windsurfTriggerManual, windsurfTriggerModelDecision, windsurfTriggerGlob)
+	}
+
+	yamlWithFences, err := frontmatter.Wrap(rule)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal windsurf rule frontmatter: %w", err)
+	}
+	out := yamlWithFences + strings.TrimLeft(joinMemories(memories), "\n")
+	if err := checkWindsurfLimit("rule", out, windsurfRuleMaxChars); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// FormatCommand processes command definitions for Windsurf agent (workflows)
+func (w *Windsurf) FormatCommand(commands []model.Command) (string, error) {
+	var outputs []string
+	for _, cmd := range commands {
+		type windsurfWorkflow struct {
+			Description string `yaml:"description,omitempty"`
+		}
+		var fm windsurfWorkflow
+		if cmd.Raw != nil {
+			_ = cmd.UnmarshalSection("windsurf", &fm)
+		}
+		warnToolsIgnored(w.ID(), cmd.Path, cmd.Raw)
+		// Fallback to common description if not provided under windsurf
+		if fm.Description == "" {
+			fm.Description = cmd.Description
+		}
+		if fm.Description == "" {
+			outputs = append(outputs, cmd.Content)
+			continue
+		}
+		yamlWithFences, err := frontmatter.Wrap(fm)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal windsurf workflow frontmatter: %w", err)
+		}
+		outputs = append(outputs, yamlWithFences+cmd.Content)
+	}
+
+	out := strings.Join(outputs, "\n\n")
+	if err := checkWindsurfLimit("workflow", out, windsurfWorkflowMaxChars); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// checkWindsurfLimit returns an error if content exceeds Windsurf's per-file character limit
+func checkWindsurfLimit(kind, content string, maxChars int) error {
+	if n := utf8.RuneCountInString(content); n > maxChars {
+		return fmt.Errorf("windsurf %s is %d characters, exceeding the %d character limit; split the source into smaller files",
+			kind, n, maxChars)
+	}
+	return nil
+}
+
+// MemoryPath returns the default path for Windsurf agent memory files.
+// Windsurf's only user-level rule file (global_rules.md) has no rule frontmatter,
+// so there is no default path in user scope.
+func (w *Windsurf) MemoryPath(userScope bool) string {
+	if userScope {
+		return ""
+	}
+	return ".windsurf/rules/"
+}
+
+// CommandPath returns the default path for Windsurf agent command files
+func (w *Windsurf) CommandPath(userScope bool) string {
+	if userScope {
+		return ".codeium/windsurf/global_workflows/"
+	}
+	return ".windsurf/workflows/"
+}
+
+// FormatMode processes mode definitions for Windsurf agent
+func (w *Windsurf) FormatMode(modes []model.Mode) (string, error) {
+	return "", fmt.Errorf("windsurf agent does not support modes")
+}
+
+// ModePath returns the default path for Windsurf agent mode files
+func (w *Windsurf) ModePath(userScope bool) string {
+	return ""
+}
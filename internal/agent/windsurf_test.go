@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestWindsurf_ID_Name(t *testing.T) {
+	w := &Windsurf{}
+	if w.ID() != "windsurf" {
+		t.Errorf("expected ID 'windsurf', got %q", w.ID())
+	}
+	if w.Name() != "Windsurf" {
+		t.Errorf("expected Name 'Windsurf', got %q", w.Name())
+	}
+}
+
+func TestWindsurf_Paths(t *testing.T) {
+	w := &Windsurf{}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"MemoryPath(false)", w.MemoryPath(false), ".windsurf/rules/"},
+		{"MemoryPath(true)", w.MemoryPath(true), ""},
+		{"CommandPath(false)", w.CommandPath(false), ".windsurf/workflows/"},
+		{"CommandPath(true)", w.CommandPath(true), ".codeium/windsurf/global_workflows/"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestWindsurf_FormatMemory(t *testing.T) {
+	w := &Windsurf{}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "no section defaults to always_on",
+			content: "# Rules",
+			want:    "---\ntrigger: always_on\n---\n\n# Rules",
+		},
+		{
+			name:    "globs imply glob trigger",
+			content: "---\nwindsurf:\n  globs:\n    - \"*.go\"\n    - \"*.mod\"\n---\nGo rules",
+			want:    "---\ntrigger: glob\nglobs: \"*.go,*.mod\"\n---\n\nGo rules",
+		},
+		{
+			name:    "description implies model_decision",
+			content: "---\nwindsurf:\n  description: Use for SQL\n---\nSQL rules",
+			want:    "---\ntrigger: model_decision\ndescription: Use for SQL\n---\n\nSQL rules",
+		},
+		{
+			name:    "explicit manual trigger",
+			content: "---\nwindsurf:\n  trigger: manual\n---\nManual",
+			want:    "---\ntrigger: manual\n---\n\nManual",
+		},
+		{
+			name:    "unknown trigger",
+			content: "---\nwindsurf:\n  trigger: sometimes\n---\nBody",
+			wantErr: "unsupported windsurf trigger",
+		},
+		{
+			name:    "glob trigger without globs",
+			content: "---\nwindsurf:\n  trigger: glob\n---\nBody",
+			wantErr: "requires 'windsurf.globs'",
+		},
+		{
+			name:    "exceeds character limit",
+			content: strings.Repeat("a", windsurfRuleMaxChars),
+			wantErr: "exceeding the 6000 character limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := w.FormatMemory(parseMemories(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FormatMemory() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatMemory() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMemory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindsurf_FormatCommand(t *testing.T) {
+	w := &Windsurf{}
+
+	got, err := w.FormatCommand([]model.Command{{Description: "Deploy the app", Content: "1. Build\n2. Ship", Raw: map[string]any{}}})
+	if err != nil {
+		t.Fatalf("FormatCommand() error = %v", err)
+	}
+	want := "---\ndescription: Deploy the app\n---\n\n1. Build\n2. Ship"
+	if got != want {
+		t.Errorf("FormatCommand() = %q, want %q", got, want)
+	}
+
+	// Workflows allow more characters than rules
+	if _, err := w.FormatCommand([]model.Command{{Content: strings.Repeat("a", windsurfRuleMaxChars+1)}}); err != nil {
+		t.Errorf("FormatCommand() error = %v for a workflow within its limit", err)
+	}
+
+	_, err = w.FormatCommand([]model.Command{{Content: strings.Repeat("a", windsurfWorkflowMaxChars+1)}})
+	if err == nil || !strings.Contains(err.Error(), "windsurf workflow") {
+		t.Errorf("expected workflow character limit error, got %v", err)
+	}
+}
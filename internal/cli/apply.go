@@ -7,6 +7,7 @@ import (
 	"github.com/uphy/agent-sync/internal/config"
 	"github.com/uphy/agent-sync/internal/log"
 	"github.com/uphy/agent-sync/internal/processor"
+	"github.com/uphy/agent-sync/internal/util"
 	"github.com/urfave/cli/v3"
 	"go.uber.org/zap"
 )
@@ -23,6 +24,16 @@ func NewApplyCommand() *cli.Command {
 				Usage:   "Preview files that would be generated (showing paths, sizes, and whether files would be created or overwritten)",
 				Sources: cli.EnvVars("AGENT_SYNC_DRY_RUN"),
 			},
+			&cli.BoolFlag{
+				Name:    "check",
+				Usage:   "Check that generated files are up to date without writing them; exits with status 3 listing stale, missing and orphaned files",
+				Sources: cli.EnvVars("AGENT_SYNC_CHECK"),
+			},
+			&cli.BoolFlag{
+				Name:    "project-only",
+				Usage:   "With --check, check project outputs only and skip user-level tasks",
+				Sources: cli.EnvVars("AGENT_SYNC_PROJECT_ONLY"),
+			},
 			&cli.BoolFlag{
 				Name:    "force",
 				Aliases: []string{"f"},
@@ -43,6 +54,8 @@ func NewApplyCommand() *cli.Command {
 
 			// Get command-specific flags
 			dryRun := cmd.Bool("dry-run")
+			check := cmd.Bool("check")
+			projectOnly := cmd.Bool("project-only")
 			force := cmd.Bool("force")
 			configPath := cmd.String("config")
 
@@ -58,6 +71,8 @@ func NewApplyCommand() *cli.Command {
 				logger.Info("Executing apply command",
 					zap.String("configPath", configPath),
 					zap.Bool("dryRun", dryRun),
+					zap.Bool("check", check),
+					zap.Bool("projectOnly", projectOnly),
 					zap.Bool("force", force))
 			}
 
@@ -78,8 +93,42 @@ func NewApplyCommand() *cli.Command {
 				return err
 			}
 
+			// In check mode, report out-of-date files as an error for a distinct exit status
+			if check {
+				changes, err := mgr.Check(projectOnly)
+				if err != nil {
+					return err
+				}
+				if err := outOfDateError(changes); err != nil {
+					return err
+				}
+				if output != nil {
+					output.PrintSuccess("Generated files are up to date")
+				}
+				return nil
+			}
+
 			// Execute apply
 			return mgr.Apply(dryRun, force)
 		},
 	}
 }
+
+// outOfDateError returns an error listing the files that differ from the generated content, or nil if there are none
+func outOfDateError(changes []processor.FileChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	err := &util.ErrOutputsOutOfDate{}
+	for _, change := range changes {
+		switch change.Kind {
+		case processor.ChangeCreate:
+			err.Missing = append(err.Missing, change.Path)
+		case processor.ChangeModify:
+			err.Stale = append(err.Stale, change.Path)
+		case processor.ChangeDelete:
+			err.Orphaned = append(err.Orphaned, change.Path)
+		}
+	}
+	return err
+}
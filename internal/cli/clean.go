@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/uphy/agent-sync/internal/config"
+	"github.com/uphy/agent-sync/internal/log"
+	"github.com/uphy/agent-sync/internal/processor"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/zap"
+)
+
+// NewCleanCommand returns the 'clean' command for urfave/cli.
+func NewCleanCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "clean",
+		Usage: "Remove files generated by agent-sync.yml",
+		Description: "Remove the files previously generated for projects and user-level tasks, as recorded in the manifest\n" +
+			"(" + processor.ManifestPath + ") of each output directory. Files modified since they were generated are kept unless --force is given.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Usage:   "Preview files that would be deleted",
+				Sources: cli.EnvVars("AGENT_SYNC_DRY_RUN"),
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Also delete generated files that were modified since they were generated",
+				Sources: cli.EnvVars("AGENT_SYNC_FORCE"),
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to agent-sync.yml file or directory containing it",
+				Value:   ".",
+				Sources: cli.EnvVars("AGENT_SYNC_CONFIG"),
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			// Access the shared context from metadata
+			sharedContext := GetSharedContext(cmd)
+
+			// Get command-specific flags
+			dryRun := cmd.Bool("dry-run")
+			force := cmd.Bool("force")
+			configPath := cmd.String("config")
+
+			var logger *zap.Logger
+			var output log.OutputWriter
+
+			// Get logger and output from shared context
+			if sharedContext != nil {
+				logger = sharedContext.Logger
+				output = sharedContext.Output
+
+				// Log command execution
+				logger.Info("Executing clean command",
+					zap.String("configPath", configPath),
+					zap.Bool("dryRun", dryRun),
+					zap.Bool("force", force))
+			}
+
+			absConfigPath, err := filepath.Abs(configPath)
+			if err != nil {
+				return err
+			}
+
+			// Validate config against embedded JSON Schema, as for apply
+			if err := config.ValidateConfigFile(absConfigPath); err != nil {
+				return err
+			}
+
+			mgr, err := processor.NewManager(absConfigPath, logger, output)
+			if err != nil {
+				return err
+			}
+
+			// Execute clean
+			return mgr.Clean(dryRun, force)
+		},
+	}
+}
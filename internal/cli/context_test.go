@@ -445,6 +445,14 @@ func (m *mockOutputWriter) PrintVerbose(msg string) {
 	m.LastPrintMessage = msg
 }
 
+func (m *mockOutputWriter) PrintDiff(diff string) {
+	m.LastPrintMessage = diff
+}
+
 func (m *mockOutputWriter) Confirm(prompt string) bool {
 	return true
 }
+
+func (m *mockOutputWriter) IsInteractive() bool {
+	return true
+}
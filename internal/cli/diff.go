@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/uphy/agent-sync/internal/config"
+	"github.com/uphy/agent-sync/internal/log"
+	"github.com/uphy/agent-sync/internal/processor"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/zap"
+)
+
+// NewDiffCommand returns the 'diff' command for urfave/cli.
+func NewDiffCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "Show the changes 'apply' would make as unified diffs",
+		Description: "Plan all projects and user-level tasks as 'apply' does, without writing files, and print a unified diff\n" +
+			"for every file that would be created, modified or deleted.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to agent-sync.yml file or directory containing it",
+				Value:   ".",
+				Sources: cli.EnvVars("AGENT_SYNC_CONFIG"),
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			// Access the shared context from metadata
+			sharedContext := GetSharedContext(cmd)
+
+			// Get command-specific flags
+			configPath := cmd.String("config")
+
+			var logger *zap.Logger
+			var output log.OutputWriter
+
+			// Get logger and output from shared context
+			if sharedContext != nil {
+				logger = sharedContext.Logger
+				output = sharedContext.Output
+
+				// Log command execution
+				logger.Info("Executing diff command",
+					zap.String("configPath", configPath))
+			}
+
+			absConfigPath, err := filepath.Abs(configPath)
+			if err != nil {
+				return err
+			}
+
+			// Validate config against embedded JSON Schema, as for apply
+			if err := config.ValidateConfigFile(absConfigPath); err != nil {
+				return err
+			}
+
+			mgr, err := processor.NewManager(absConfigPath, logger, output)
+			if err != nil {
+				return err
+			}
+
+			// Execute diff; differences are printed, not reported as an error
+			_, err = mgr.Diff()
+			return err
+		},
+	}
+}
@@ -135,6 +135,8 @@ func InitializeLogging(ctx context.Context, cmd *cli.Command) (context.Context,
 
 	// Store logger in app metadata for global access if needed
 	cmd.Metadata["logger"] = logger
+	// Warnings of the agent formatters go through the global logger
+	log.SetGlobalLogger(logger)
 
 	return ctx, nil
 }
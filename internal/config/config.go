@@ -15,6 +15,8 @@ type Config struct {
 	Tasks      []Task   `yaml:"tasks,omitempty"`
 	// User holds global user-level configuration
 	User UserConfig `yaml:"user"`
+	// Vars are template variables available to all tasks
+	Vars map[string]any `yaml:"vars,omitempty"`
 }
 
 // SetDefaultNames sets default names for all tasks across all projects and user config
@@ -38,6 +40,8 @@ type Project struct {
 	OutputDirs []string `yaml:"outputDirs"`
 	// Tasks is the list of generation tasks for this project
 	Tasks []Task `yaml:"tasks"`
+	// Vars are template variables for the tasks of this project, overriding root-level vars
+	Vars map[string]any `yaml:"vars,omitempty"`
 }
 
 // SetDefaultNames sets default names for all tasks in this project
@@ -62,16 +66,18 @@ func (u *UserConfig) SetDefaultNames() {
 	}
 }
 
-// Task represents a single generation task (command, memory, or mode)
+// Task represents a single generation task (command, memory, mode, skill, mcp, ignore, or settings)
 type Task struct {
 	// Name is an optional identifier for the task
 	Name string `yaml:"name,omitempty"`
-	// Type is either "command", "memory", or "mode"
+	// Type is one of "command", "memory", "mode", "skill", "mcp", "ignore", or "settings"
 	Type string `yaml:"type"`
 	// Inputs are file or directory paths relative to config directory
 	Inputs []string `yaml:"inputs"`
 	// Outputs define the output agents and paths
 	Outputs []Output `yaml:"outputs"`
+	// Vars are template variables for this task, overriding project and root-level vars
+	Vars map[string]any `yaml:"vars,omitempty"`
 }
 
 // SetDefaultName sets a default name for a task if one is not provided
@@ -90,4 +96,37 @@ type Output struct {
 	// - If path ends with "/", it will be treated as a directory (non-concatenated outputs)
 	// - If path doesn't end with "/", it will be treated as a file (concatenated outputs)
 	OutputPath string `yaml:"outputPath,omitempty"`
+	// Merge merges the generated content into an existing output file instead of
+	// overwriting it. Only supported by task types producing configuration files (e.g. mcp, ignore).
+	Merge bool `yaml:"merge,omitempty"`
+	// Mode controls how the generated content is written to the output file:
+	// "overwrite" (default) replaces the file, "managed-block" only replaces the
+	// region between agent-sync markers and keeps the rest of the file as written.
+	Mode string `yaml:"mode,omitempty"`
+	// Vars are template variables for this output, overriding task, project and root-level vars
+	Vars map[string]any `yaml:"vars,omitempty"`
+}
+
+// Output write modes
+const (
+	OutputModeOverwrite    = "overwrite"
+	OutputModeManagedBlock = "managed-block"
+)
+
+// MergeVars merges template variables from the least to the most specific level.
+// Keys of later levels override those of earlier ones; nested maps are merged key by key.
+// The result is never nil.
+func MergeVars(levels ...map[string]any) map[string]any {
+	merged := make(map[string]any)
+	for _, vars := range levels {
+		for key, value := range vars {
+			if nested, ok := value.(map[string]any); ok {
+				if base, ok := merged[key].(map[string]any); ok {
+					value = MergeVars(base, nested)
+				}
+			}
+			merged[key] = value
+		}
+	}
+	return merged
 }
@@ -105,3 +105,50 @@ user:
 	expectedOutputPath := filepath.Join(mockHome, "custom", "path")
 	assert.Equal(t, expectedOutputPath, cfg.Projects["test-project"].Tasks[0].Outputs[0].OutputPath)
 }
+
+func TestLoadConfig_Vars(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "agent-sync.yml")
+	err := os.WriteFile(configPath, []byte(`
+configVersion: "1.0"
+vars:
+  repo: agent-sync
+  team:
+    lead: alice
+    size: 3
+projects:
+  web:
+    outputDirs: [out]
+    vars:
+      team:
+        lead: bob
+    tasks:
+      - type: memory
+        inputs: [memories]
+        vars:
+          repo: web
+        outputs:
+          - agent: claude
+            vars:
+              extra: true
+`), 0644)
+	assert.NoError(t, err)
+
+	cfg, _, err := LoadConfig(configPath)
+	assert.NoError(t, err)
+	proj := cfg.Projects["web"]
+	task := proj.Tasks[0]
+
+	vars := MergeVars(cfg.Vars, proj.Vars, task.Vars, task.Outputs[0].Vars)
+	assert.Equal(t, "web", vars["repo"])
+	assert.Equal(t, true, vars["extra"])
+	// Nested maps are merged key by key
+	team, ok := vars["team"].(map[string]any)
+	assert.True(t, ok, "expected team to be a map, got %T", vars["team"])
+	assert.Equal(t, "bob", team["lead"])
+	assert.EqualValues(t, 3, team["size"])
+
+	// Merging does not modify the configured levels
+	assert.Equal(t, "alice", cfg.Vars["team"].(map[string]any)["lead"])
+	assert.NotNil(t, MergeVars())
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/goccy/go-yaml"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/uphy/agent-sync/internal/util"
 )
 
 // ValidationIssue represents a single schema validation issue.
@@ -33,6 +34,11 @@ func (e *ValidationError) Error() string {
 	return b.String()
 }
 
+// ExitCode returns the exit status reported for schema violations
+func (e *ValidationError) ExitCode() int {
+	return util.ExitCodeInvalidConfig
+}
+
 // ValidateConfigFile validates the provided YAML configuration file against the JSON Schema.
 //
 // Behavior:
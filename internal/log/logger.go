@@ -156,6 +156,13 @@ func InitGlobalLogger(config Config) error {
 	return nil
 }
 
+// SetGlobalLogger は既存のロガーをグローバルロガーとして設定する
+func SetGlobalLogger(logger *zap.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	globalLogger = logger
+}
+
 // GetLogger はグローバルロガーを取得する
 // グローバルロガーが初期化されていない場合はデフォルト設定で初期化する
 func GetLogger() *zap.Logger {
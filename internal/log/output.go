@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 )
 
 // OutputWriter はユーザー向け出力を扱うインターフェース
@@ -27,8 +28,14 @@ type OutputWriter interface {
 	// 詳細モード時のみ出力
 	PrintVerbose(msg string)
 
+	// unified diff を出力 (追加・削除行を色分け)
+	PrintDiff(diff string)
+
 	// ユーザーの確認を得る
 	Confirm(prompt string) bool
+
+	// 対話的な確認が可能か (標準入力が端末か) を返す
+	IsInteractive() bool
 }
 
 // ConsoleOutput は標準出力へのOutputWriter実装
@@ -97,6 +104,39 @@ func (c *ConsoleOutput) PrintVerbose(msg string) {
 	}
 }
 
+// PrintDiff outputs a unified diff, coloring file headers, hunk headers, and added and removed lines
+func (c *ConsoleOutput) PrintDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		if !c.Color {
+			fmt.Println(line)
+			continue
+		}
+		var attr color.Attribute
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			attr = color.Bold
+		case strings.HasPrefix(line, "@@"):
+			attr = color.FgCyan
+		case strings.HasPrefix(line, "+"):
+			attr = color.FgGreen
+		case strings.HasPrefix(line, "-"):
+			attr = color.FgRed
+		default:
+			fmt.Println(line)
+			continue
+		}
+		if _, err := color.New(attr).Println(line); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print diff: %v\n", err)
+		}
+	}
+}
+
+// IsInteractive reports whether standard input is a terminal, so that Confirm can ask the user
+func (c *ConsoleOutput) IsInteractive() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 // Confirm asks for user confirmation
 func (c *ConsoleOutput) Confirm(prompt string) bool {
 	reader := bufio.NewReader(os.Stdin)
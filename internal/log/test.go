@@ -12,8 +12,10 @@ type TestOutput struct {
 	ProgressMsgs   []string
 	SuccessMsgs    []string
 	VerboseMsgs    []string
+	Diffs          []string
 	ConfirmPrompts []string
 	ConfirmReturn  bool // Confirmメソッドの戻り値を制御
+	Interactive    bool // IsInteractiveメソッドの戻り値を制御
 	Verbose        bool // 詳細出力モードの制御
 }
 
@@ -25,8 +27,10 @@ func NewTestOutput(verbose bool) *TestOutput {
 		ProgressMsgs:   []string{},
 		SuccessMsgs:    []string{},
 		VerboseMsgs:    []string{},
+		Diffs:          []string{},
 		ConfirmPrompts: []string{},
 		ConfirmReturn:  false,
+		Interactive:    true,
 		Verbose:        verbose,
 	}
 }
@@ -67,12 +71,22 @@ func (t *TestOutput) PrintVerbose(msg string) {
 	}
 }
 
+// PrintDiff records a unified diff
+func (t *TestOutput) PrintDiff(diff string) {
+	t.Diffs = append(t.Diffs, diff)
+}
+
 // Confirm simulates user confirmation
 func (t *TestOutput) Confirm(prompt string) bool {
 	t.ConfirmPrompts = append(t.ConfirmPrompts, prompt)
 	return t.ConfirmReturn
 }
 
+// IsInteractive simulates whether confirmation prompts are possible
+func (t *TestOutput) IsInteractive() bool {
+	return t.Interactive
+}
+
 // SetConfirmReturn sets the return value for Confirm method
 func (t *TestOutput) SetConfirmReturn(val bool) {
 	t.ConfirmReturn = val
@@ -0,0 +1,17 @@
+package model
+
+import "strings"
+
+// ParseIgnorePatterns parses gitignore-style content into its patterns.
+// Blank lines and comments are dropped; negated patterns keep their '!' prefix.
+func ParseIgnorePatterns(content string) []string {
+	var patterns []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
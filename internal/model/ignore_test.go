@@ -0,0 +1,14 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIgnorePatterns(t *testing.T) {
+	got := ParseIgnorePatterns("# secrets\n.env\n\nsecrets/  \n!secrets/README.md\r\n")
+	want := []string{".env", "secrets/", "!secrets/README.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseIgnorePatterns() = %v, want %v", got, want)
+	}
+}
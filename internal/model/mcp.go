@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// MCP server transport types
+const (
+	MCPTypeStdio = "stdio"
+	MCPTypeHTTP  = "http"
+	MCPTypeSSE   = "sse"
+)
+
+// MCPServer represents an MCP server definition in an agent-agnostic way.
+// Local servers set Command; remote servers set URL.
+type MCPServer struct {
+	// Name is the key of the server in the source file
+	Name string `yaml:"-"`
+	// Type is the transport: "stdio", "http", or "sse".
+	// It defaults to "stdio" for local servers and "http" for remote servers.
+	Type string `yaml:"type"`
+	// Command, Args and Env configure a local (stdio) server
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+	// URL and Headers configure a remote (http or sse) server
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	// Disabled keeps the server configured but turned off for agents that support it
+	Disabled bool `yaml:"disabled"`
+	// Raw contains the full server definition as a generic map. Agent-specific
+	// keys like "roo" or "cline" remain here.
+	Raw map[string]any `yaml:"-"`
+}
+
+// IsRemote reports whether the server is reached over the network
+func (s *MCPServer) IsRemote() bool {
+	return s.Type != MCPTypeStdio
+}
+
+// ParseMCPServers parses a neutral MCP server list of the form
+//
+//	servers:
+//	  <name>:
+//	    command: ...
+//
+// into MCP server definitions sorted by name.
+func ParseMCPServers(path string, content []byte) ([]MCPServer, error) {
+	var doc struct {
+		Servers map[string]map[string]any `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP servers in %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(doc.Servers))
+	for name := range doc.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	servers := make([]MCPServer, 0, len(names))
+	for _, name := range names {
+		raw := doc.Servers[name]
+		b, err := yaml.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal MCP server %q: %w", name, err)
+		}
+		var s MCPServer
+		if err := yaml.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse MCP server %q in %s: %w", name, path, err)
+		}
+		s.Name = name
+		s.Raw = raw
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("invalid MCP server %q in %s: %w", name, path, err)
+		}
+		servers = append(servers, s)
+	}
+	return servers, nil
+}
+
+// validate checks the transport settings and fills in the default type.
+func (s *MCPServer) validate() error {
+	switch {
+	case s.Command != "" && s.URL != "":
+		return fmt.Errorf("command and url are mutually exclusive")
+	case s.Command == "" && s.URL == "":
+		return fmt.Errorf("either command or url is required")
+	}
+	if s.Type == "" {
+		s.Type = MCPTypeStdio
+		if s.URL != "" {
+			s.Type = MCPTypeHTTP
+		}
+	}
+	switch s.Type {
+	case MCPTypeStdio:
+		if s.Command == "" {
+			return fmt.Errorf("type %q requires command", s.Type)
+		}
+	case MCPTypeHTTP, MCPTypeSSE:
+		if s.URL == "" {
+			return fmt.Errorf("type %q requires url", s.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported type %q (must be one of stdio, http, sse)", s.Type)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMCPServers(t *testing.T) {
+	src := `servers:
+  github:
+    command: npx
+    args: ["-y", "@modelcontextprotocol/server-github"]
+    env:
+      GITHUB_TOKEN: "${GITHUB_TOKEN}"
+    roo:
+      alwaysAllow: [list_issues]
+  docs:
+    url: https://example.com/mcp
+  events:
+    type: sse
+    url: https://example.com/sse
+`
+	servers, err := ParseMCPServers("mcp.yml", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseMCPServers() error = %v", err)
+	}
+
+	var names, types []string
+	for _, s := range servers {
+		names = append(names, s.Name)
+		types = append(types, s.Type)
+	}
+	if want := []string{"docs", "events", "github"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	if want := []string{MCPTypeHTTP, MCPTypeSSE, MCPTypeStdio}; !reflect.DeepEqual(types, want) {
+		t.Errorf("types = %v, want %v", types, want)
+	}
+
+	gh := servers[2]
+	if gh.Command != "npx" || len(gh.Args) != 2 || gh.Env["GITHUB_TOKEN"] != "${GITHUB_TOKEN}" {
+		t.Errorf("unexpected github server: %+v", gh)
+	}
+	roo, ok := gh.Raw["roo"].(map[string]any)
+	if !ok || !reflect.DeepEqual(roo["alwaysAllow"], []any{"list_issues"}) {
+		t.Errorf("expected the roo section to be kept in Raw, got %v", gh.Raw)
+	}
+}
+
+func TestParseMCPServers_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"no transport", "servers:\n  x:\n    args: [a]\n", "either command or url is required"},
+		{"both transports", "servers:\n  x:\n    command: a\n    url: http://b\n", "mutually exclusive"},
+		{"stdio with url", "servers:\n  x:\n    type: stdio\n    url: http://b\n", "requires command"},
+		{"unknown type", "servers:\n  x:\n    type: ws\n    url: http://b\n", "unsupported type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMCPServers("mcp.yml", []byte(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseMCPServers() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/frontmatter"
+)
+
+// Memory represents a memory context in an agent-agnostic way.
+// Frontmatter keys are preserved in Raw and stripped from Content.
+type Memory struct {
+	// Description is parsed from top-level frontmatter key "description".
+	// Agent-specific descriptions override this during formatting if present.
+	Description string `yaml:"-"`
+	// Raw contains the full frontmatter as a generic map. Agent-specific
+	// keys like "cursor", "windsurf", "kiro", "copilot" remain here.
+	Raw map[string]any `yaml:"-"`
+	// Content is the markdown body after frontmatter.
+	Content string `yaml:"-"`
+	// Path is the original file path (not in frontmatter)
+	Path string `yaml:"-"`
+}
+
+// ParseMemory parses a memory context from file content into an agent-agnostic Memory.
+// Content without frontmatter is preserved exactly so plain markdown memories pass through unchanged.
+func ParseMemory(path string, content []byte) (*Memory, error) {
+	fm, body, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	memory := &Memory{
+		Path:    path,
+		Content: body,
+		Raw:     map[string]any{},
+	}
+
+	// Preserve frontmatter generically by deep-converting into map[string]any
+	if len(fm) > 0 {
+		yamlBytes, err := yaml.Marshal(fm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal frontmatter: %w", err)
+		}
+		tmp := map[string]any{}
+		if err := yaml.Unmarshal(yamlBytes, &tmp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal frontmatter to raw map: %w", err)
+		}
+		memory.Raw = tmp
+
+		// Extract common description from top-level if present
+		if v, ok := tmp["description"]; ok {
+			if s, ok := v.(string); ok {
+				memory.Description = s
+			}
+		}
+	}
+
+	return memory, nil
+}
@@ -0,0 +1,34 @@
+package model
+
+import "testing"
+
+func TestParseMemory_StripsFrontmatter(t *testing.T) {
+	m, err := ParseMemory("memories/go.md", []byte("---\ndescription: Go rules\ncopilot:\n  applyTo: \"**/*.go\"\n---\n# Go\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if m.Content != "# Go\n" {
+		t.Errorf("expected frontmatter to be stripped, got %q", m.Content)
+	}
+	if m.Description != "Go rules" {
+		t.Errorf("expected Description 'Go rules', got %q", m.Description)
+	}
+	copilot, ok := m.Raw["copilot"].(map[string]any)
+	if !ok || copilot["applyTo"] != "**/*.go" {
+		t.Errorf("expected the copilot section to be kept in Raw, got %v", m.Raw)
+	}
+}
+
+func TestParseMemory_PreservesPlainContent(t *testing.T) {
+	content := "\n# Rules\n\nNo frontmatter here.\n"
+	m, err := ParseMemory("memories/rules.md", []byte(content))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if m.Content != content {
+		t.Errorf("expected content to be preserved, got %q", m.Content)
+	}
+	if len(m.Raw) != 0 {
+		t.Errorf("expected empty Raw, got %v", m.Raw)
+	}
+}
@@ -19,6 +19,8 @@ type Mode struct {
 	Raw map[string]any `yaml:"-"`
 	// Content is the markdown body after frontmatter.
 	Content string
+	// Path is the original file path (not in frontmatter)
+	Path string `yaml:"-"`
 }
 
 // UnmarshalSection marshals a sub-map from m.Raw[key] to YAML and unmarshals into out.
@@ -55,6 +57,7 @@ func ParseMode(path string, content []byte) (*Mode, error) {
 	mode := &Mode{
 		Content: body,
 		Raw:     map[string]any{},
+		Path:    path,
 	}
 
 	// Preserve frontmatter generically by deep-converting into map[string]any
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/frontmatter"
+)
+
+// SkillFileName is the name of the entry file of a skill directory
+const SkillFileName = "SKILL.md"
+
+// Skill represents a skill definition in an agent-agnostic way.
+// A skill is a directory containing a SKILL.md entry file and supporting files;
+// Skill holds the parsed entry file. Frontmatter keys are preserved in Raw.
+type Skill struct {
+	// Name is parsed from top-level frontmatter key "name".
+	// It defaults to the name of the skill directory.
+	Name string `yaml:"-"`
+	// Description is parsed from top-level frontmatter key "description".
+	// Agent-specific descriptions override this during formatting if present.
+	Description string `yaml:"-"`
+	// Raw contains the full frontmatter as a generic map. Agent-specific
+	// keys like "claude" remain here.
+	Raw map[string]any `yaml:"-"`
+	// Content is the markdown body after frontmatter.
+	Content string `yaml:"-"`
+	// Path is the original path of the SKILL.md file (not in frontmatter)
+	Path string `yaml:"-"`
+}
+
+// ParseSkill parses the SKILL.md entry file of a skill into an agent-agnostic Skill.
+// path is the path of the SKILL.md file; its directory name is the default skill name.
+func ParseSkill(path string, content []byte) (*Skill, error) {
+	fm, body, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	skill := &Skill{
+		Name:    filepath.Base(filepath.Dir(path)),
+		Path:    path,
+		Content: body,
+		Raw:     map[string]any{},
+	}
+
+	// Preserve frontmatter generically by deep-converting into map[string]any
+	if len(fm) > 0 {
+		yamlBytes, err := yaml.Marshal(fm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal frontmatter: %w", err)
+		}
+		tmp := map[string]any{}
+		if err := yaml.Unmarshal(yamlBytes, &tmp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal frontmatter to raw map: %w", err)
+		}
+		skill.Raw = tmp
+
+		// Extract common name and description from top-level if present
+		if s, ok := tmp["name"].(string); ok && s != "" {
+			skill.Name = s
+		}
+		if s, ok := tmp["description"].(string); ok {
+			skill.Description = s
+		}
+	}
+
+	return skill, nil
+}
@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// IgnoreProcessor processes ignore tasks.
+// Inputs are gitignore-style files whose patterns are combined into each agent's ignore mechanism.
+type IgnoreProcessor struct {
+	*BaseProcessor
+}
+
+// NewIgnoreProcessor creates a new IgnoreProcessor
+func NewIgnoreProcessor(base *BaseProcessor) *IgnoreProcessor {
+	return &IgnoreProcessor{BaseProcessor: base}
+}
+
+// ignoreSource is an ignore source file; its patterns are parsed after templating
+type ignoreSource struct {
+	content string
+}
+
+// ignoreStrategy provides parsing, content access, and formatting for ignore files
+type ignoreStrategy struct {
+	p         *BaseProcessor
+	agentName string
+}
+
+func (s ignoreStrategy) Parse(absPath string, raw []byte) (ignoreSource, error) {
+	return ignoreSource{content: string(raw)}, nil
+}
+
+func (s ignoreStrategy) GetContent(item ignoreSource) string {
+	return item.content
+}
+
+func (s ignoreStrategy) SetContent(item ignoreSource, content string) ignoreSource {
+	item.content = content
+	return item
+}
+
+func (s ignoreStrategy) FormatOne(a agent.Agent, item ignoreSource) (string, error) {
+	return s.FormatMany(a, []ignoreSource{item})
+}
+
+func (s ignoreStrategy) FormatMany(a agent.Agent, items []ignoreSource) (string, error) {
+	f, ok := a.(agent.IgnoreFormatter)
+	if !ok {
+		return "", fmt.Errorf("agent %s does not support ignore files", a.ID())
+	}
+
+	// Combine the patterns of all sources, keeping the first occurrence of each
+	var patterns []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		for _, pattern := range model.ParseIgnorePatterns(item.content) {
+			if seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	return f.FormatIgnore(patterns)
+}
+
+// Process implements the task processing for ignore task type
+func (p *IgnoreProcessor) Process(inputs []string, cfg *OutputConfig) (*TaskResult, error) {
+	if _, ok := cfg.Agent.(agent.IgnoreFormatter); !ok {
+		return nil, fmt.Errorf("agent %s does not support ignore files", cfg.AgentName)
+	}
+	if cfg.RelPath == "" {
+		return nil, fmt.Errorf("agent %s has no default ignore file path in this scope; set outputPath", cfg.AgentName)
+	}
+	if cfg.IsDirectory {
+		return nil, fmt.Errorf("ignore output path must be a file: %s", cfg.RelPath)
+	}
+
+	strategy := ignoreStrategy{p: p.BaseProcessor, agentName: cfg.AgentName}
+	result, err := processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON targets are settings files shared with other configuration, so they are always merged
+	if isJSONPath(cfg.RelPath) {
+		for i := range result.Files {
+			result.Files[i].merge = func(existing []byte, generated string, owned []string) (string, []string, error) {
+				return p.MergeContent(cfg, existing, generated, owned)
+			}
+		}
+	}
+	return result, nil
+}
+
+// MergeContent merges generated ignore rules into an existing file.
+// Settings files are deep-merged with deny lists combined; ignore files keep
+// their existing lines and gain the missing patterns. Rules generated by the
+// previous run that are no longer generated are removed in both cases.
+func (p *IgnoreProcessor) MergeContent(cfg *OutputConfig, existing []byte, generated string, owned []string) (string, []string, error) {
+	if isJSONPath(cfg.RelPath) {
+		return mergeJSON(-1, true)(existing, generated, owned)
+	}
+	return mergeLines(existing, generated, owned)
+}
+
+// GetOutputPath returns the appropriate output path for ignore tasks
+func (p *IgnoreProcessor) GetOutputPath(a agent.Agent, outputPath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+	if f, ok := a.(agent.IgnoreFormatter); ok {
+		return f.IgnorePath(p.userScope)
+	}
+	return ""
+}
+
+// isJSONPath reports whether an output path refers to a JSON file
+func isJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
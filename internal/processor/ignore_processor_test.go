@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/util"
+	"go.uber.org/zap"
+)
+
+// TestIgnoreProcessor_Process verifies that patterns from all inputs are combined
+// without duplicates and that JSON targets are always merged.
+func TestIgnoreProcessor_Process(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"ignore/common": "# shared\n.env\nsecrets/\n",
+		"ignore/extra":  ".env\n*.pem\n",
+	})
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	ip := NewIgnoreProcessor(base)
+	inputs := []string{"ignore/common", "ignore/extra"}
+
+	cfg := &OutputConfig{Agent: &agent.Roo{}, AgentName: "roo", TaskType: "ignore"}
+	cfg.RelPath = ip.GetOutputPath(cfg.Agent, "")
+	result, err := ip.Process(inputs, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if got, want := result.Files[0].Content, ".env\nsecrets/\n*.pem\n"; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+	if result.Files[0].merge != nil {
+		t.Error("ignore files must not be merged unless requested")
+	}
+
+	cfg = &OutputConfig{Agent: &agent.Claude{}, AgentName: "claude", TaskType: "ignore"}
+	cfg.RelPath = ip.GetOutputPath(cfg.Agent, "")
+	result, err = ip.Process(inputs, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if result.Files[0].merge == nil {
+		t.Fatal("expected Claude settings output to be merged")
+	}
+	existing := `{"model": "sonnet", "permissions": {"deny": ["Bash(rm:*)", "Read(.env)"]}}`
+	merged, _, err := result.Files[0].merge([]byte(existing), result.Files[0].Content, nil)
+	if err != nil {
+		t.Fatalf("merge error: %v", err)
+	}
+	for _, want := range []string{`"model": "sonnet"`, `"Bash(rm:*)"`, `"Read(*.pem)"`} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("expected %s in merged settings, got:\n%s", want, merged)
+		}
+	}
+	if strings.Count(merged, `"Read(.env)"`) != 1 {
+		t.Errorf("expected Read(.env) once in merged settings, got:\n%s", merged)
+	}
+
+	// Deny rules generated by the previous run are removed once their pattern is gone
+	merged, _, err = result.Files[0].merge([]byte(`{"permissions": {"deny": ["Bash(rm:*)", "Read(old/**)"]}}`), result.Files[0].Content, []string{`/permissions/deny/"Read(old~1**)"`})
+	if err != nil {
+		t.Fatalf("merge error: %v", err)
+	}
+	if strings.Contains(merged, "Read(old/**)") || !strings.Contains(merged, "Bash(rm:*)") {
+		t.Errorf("expected only the stale generated deny rule to be removed, got:\n%s", merged)
+	}
+}
+
+func TestIgnoreProcessor_MergeContent_Lines(t *testing.T) {
+	ip := NewIgnoreProcessor(nil)
+	got, _, err := ip.MergeContent(&OutputConfig{RelPath: ".cursorignore"}, []byte("# manual\nnode_modules/\n.env\n"), ".env\nsecrets/\n", nil)
+	if err != nil {
+		t.Fatalf("MergeContent() error = %v", err)
+	}
+	if want := "# manual\nnode_modules/\n.env\nsecrets/\n"; got != want {
+		t.Errorf("MergeContent() = %q, want %q", got, want)
+	}
+
+	// Lines generated by the previous run are removed once they are no longer generated;
+	// .env was present before agent-sync generated it and stays with its owner
+	got, owned, err := ip.MergeContent(&OutputConfig{RelPath: ".cursorignore"}, []byte("# manual\n.env\nold/\nsecrets/\n"), ".env\nsecrets/\n", []string{"old/", "secrets/"})
+	if err != nil {
+		t.Fatalf("MergeContent() error = %v", err)
+	}
+	if want := "# manual\n.env\nsecrets/\n"; got != want {
+		t.Errorf("MergeContent() = %q, want %q", got, want)
+	}
+	if len(owned) != 1 || owned[0] != "secrets/" {
+		t.Errorf("owned = %v, want [secrets/]", owned)
+	}
+
+	// Without an existing file the generated lines are written as they are
+	got, _, err = ip.MergeContent(&OutputConfig{RelPath: ".cursorignore"}, nil, ".env\n", nil)
+	if err != nil || got != ".env\n" {
+		t.Errorf("MergeContent() = %q, %v", got, err)
+	}
+}
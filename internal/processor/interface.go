@@ -20,6 +20,22 @@ type TaskProcessor interface {
 	GetOutputPath(agent agent.Agent, outputPath string) string
 }
 
+// InputPatternMapper is an optional interface for task processors whose inputs are not
+// plain files, allowing them to map the configured input patterns before they are resolved.
+type InputPatternMapper interface {
+	// InputPatterns returns the glob patterns used to resolve the task's input files
+	InputPatterns(patterns []string) []string
+}
+
+// MergingProcessor is an optional interface for task processors whose outputs can be
+// merged into existing files instead of overwriting them (output option 'merge').
+type MergingProcessor interface {
+	// MergeContent combines the generated content for an output with the content of the existing file,
+	// nil when there is none. owned lists what the task generated into the file the last time;
+	// owned content that is no longer generated is removed. It returns what the task owns after the merge.
+	MergeContent(cfg *OutputConfig, existing []byte, generated string, owned []string) (string, []string, error)
+}
+
 // BaseProcessor contains common functionality for all task processors
 type BaseProcessor struct {
 	fs           util.FileSystem
@@ -41,14 +57,27 @@ func NewBaseProcessor(fs util.FileSystem, logger *zap.Logger, absInputRoot strin
 }
 
 // Template engine factory kept internal to avoid repetition
-func (p *BaseProcessor) templateEngine(agentName string) *template.Engine {
+func (p *BaseProcessor) templateEngine(cfg *OutputConfig) *template.Engine {
 	fsAdapter := NewFSAdapter(p.fs)
-	return template.NewEngine(fsAdapter, agentName, p.absInputRoot, p.registry)
+	engine := template.NewEngine(fsAdapter, cfg.AgentName, p.absInputRoot, p.registry)
+	engine.Catalog = cfg.Catalog
+	return engine
+}
+
+// templateData returns the data passed to the templates rendered for the output file at relPath
+func templateData(cfg *OutputConfig, relPath string) template.Data {
+	data := cfg.TemplateData
+	data.OutputPath = filepath.ToSlash(relPath)
+	return data
 }
 
-// resolveOutputRelPath builds the per-input relative output path under cfg.RelPath
-func resolveOutputRelPath(baseRel string, input string) string {
-	return filepath.Join(baseRel, filepath.Base(input))
+// resolveOutputRelPath builds the per-input relative output path under cfg.RelPath.
+// Agents implementing agent.FileNamer decide the file name; otherwise the input's basename is kept.
+func resolveOutputRelPath(cfg *OutputConfig, input string) string {
+	if namer, ok := cfg.Agent.(agent.FileNamer); ok {
+		return filepath.Join(cfg.RelPath, namer.FileName(cfg.TaskType, input))
+	}
+	return filepath.Join(cfg.RelPath, filepath.Base(input))
 }
 
 // ProcessorStrategy provides the per-task-type behavior plugged into the generic driver.
@@ -93,10 +122,16 @@ func processGeneric[T any](
 			return nil, fmt.Errorf("parse item from content %s: %w", absInputPath, err)
 		}
 
+		// Directory outputs get one file per input, file outputs a single file
+		relPath := cfg.RelPath
+		if cfg.IsDirectory {
+			relPath = resolveOutputRelPath(cfg, input)
+		}
+
 		// Apply templating centrally using strategy-provided content accessors
-		engine := p.templateEngine(cfg.AgentName)
+		engine := p.templateEngine(cfg)
 		content := strategy.GetContent(item)
-		out, err := engine.Execute(absInputPath, content, nil)
+		out, err := engine.Execute(absInputPath, content, templateData(cfg, relPath))
 		if err != nil {
 			return nil, fmt.Errorf("template execute %s: %w", input, err)
 		}
@@ -109,7 +144,7 @@ func processGeneric[T any](
 				return nil, fmt.Errorf("format item for agent %s: %w", cfg.AgentName, err)
 			}
 			result.Files = append(result.Files, ProcessedFile{
-				relPath:   resolveOutputRelPath(cfg.RelPath, input),
+				relPath:   relPath,
 				Content:   content,
 				AgentName: cfg.AgentName,
 			})
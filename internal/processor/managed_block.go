@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Markers delimiting the region of an output file managed by agent-sync (output mode 'managed-block')
+const (
+	managedBlockBegin = "<!-- agent-sync:begin -->"
+	managedBlockEnd   = "<!-- agent-sync:end -->"
+)
+
+// Block-level changes reported by dry runs for managed-block outputs
+const (
+	managedBlockAdded     = "added"
+	managedBlockUpdated   = "updated"
+	managedBlockUnchanged = "unchanged"
+)
+
+// renderManagedBlock wraps generated content in the managed block markers
+func renderManagedBlock(generated string) string {
+	return managedBlockBegin + "\n" + strings.TrimRight(generated, "\n") + "\n" + managedBlockEnd
+}
+
+// findManagedBlock returns the offsets of the managed block in content, from the start of the
+// begin marker to the end of the end marker. found is false when content has no markers.
+func findManagedBlock(content string) (start, end int, found bool, err error) {
+	begins := strings.Count(content, managedBlockBegin)
+	ends := strings.Count(content, managedBlockEnd)
+	if begins == 0 && ends == 0 {
+		return 0, 0, false, nil
+	}
+	if begins != 1 || ends != 1 {
+		return 0, 0, false, fmt.Errorf("expected exactly one %s and one %s marker, found %d and %d",
+			managedBlockBegin, managedBlockEnd, begins, ends)
+	}
+	start = strings.Index(content, managedBlockBegin)
+	end = strings.Index(content, managedBlockEnd)
+	if end < start {
+		return 0, 0, false, fmt.Errorf("%s marker appears before %s", managedBlockEnd, managedBlockBegin)
+	}
+	return start, end + len(managedBlockEnd), true, nil
+}
+
+// applyManagedBlock replaces the managed block of an existing file with the generated content.
+// A block is appended when the file has no markers; a missing file consists of the block only.
+func applyManagedBlock(existing []byte, generated string) (string, error) {
+	block := renderManagedBlock(generated)
+	content := string(existing)
+	if strings.TrimSpace(content) == "" {
+		return block + "\n", nil
+	}
+
+	start, end, found, err := findManagedBlock(content)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + "\n" + block + "\n", nil
+	}
+	return content[:start] + block + content[end:], nil
+}
+
+// managedBlockChange describes how applying generated content changes the managed block of an existing file
+func managedBlockChange(existing []byte, generated string) (string, error) {
+	content := string(existing)
+	start, end, found, err := findManagedBlock(content)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return managedBlockAdded, nil
+	}
+	if content[start:end] == renderManagedBlock(generated) {
+		return managedBlockUnchanged, nil
+	}
+	return managedBlockUpdated, nil
+}
+
+// isManagedBlockPath reports whether an output path refers to a Markdown file, where the
+// HTML comment markers of a managed block are valid content
+func isManagedBlockPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".mdc", ".markdown":
+		return true
+	}
+	return false
+}
+
+// hasFrontmatter reports whether generated content starts with YAML frontmatter, which agents
+// only read at the very top of a file and therefore cannot be placed in a managed block
+func hasFrontmatter(content string) bool {
+	return strings.HasPrefix(content, "---\n") || strings.HasPrefix(content, "---\r\n")
+}
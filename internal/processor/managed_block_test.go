@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/config"
+	"go.uber.org/zap"
+)
+
+func TestApplyManagedBlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   string
+		want       string
+		wantChange string
+		wantErr    bool
+	}{
+		{
+			name:     "new file",
+			existing: "",
+			want:     "<!-- agent-sync:begin -->\nGenerated\n<!-- agent-sync:end -->\n",
+		},
+		{
+			name:       "append block",
+			existing:   "# Notes\nHand-written",
+			want:       "# Notes\nHand-written\n\n<!-- agent-sync:begin -->\nGenerated\n<!-- agent-sync:end -->\n",
+			wantChange: managedBlockAdded,
+		},
+		{
+			name:       "replace block",
+			existing:   "# Notes\n<!-- agent-sync:begin -->\nOld\n<!-- agent-sync:end -->\nFooter\n",
+			want:       "# Notes\n<!-- agent-sync:begin -->\nGenerated\n<!-- agent-sync:end -->\nFooter\n",
+			wantChange: managedBlockUpdated,
+		},
+		{
+			name:       "unchanged block",
+			existing:   "<!-- agent-sync:begin -->\nGenerated\n<!-- agent-sync:end -->\nFooter\n",
+			want:       "<!-- agent-sync:begin -->\nGenerated\n<!-- agent-sync:end -->\nFooter\n",
+			wantChange: managedBlockUnchanged,
+		},
+		{
+			name:     "missing end marker",
+			existing: "<!-- agent-sync:begin -->\nOld\n",
+			wantErr:  true,
+		},
+		{
+			name:     "markers out of order",
+			existing: "<!-- agent-sync:end -->\n<!-- agent-sync:begin -->\n",
+			wantErr:  true,
+		},
+		{
+			name:     "multiple blocks",
+			existing: strings.Repeat("<!-- agent-sync:begin -->\nA\n<!-- agent-sync:end -->\n", 2),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyManagedBlock([]byte(tt.existing), "Generated\n")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyManagedBlock returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("applyManagedBlock() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if tt.wantChange == "" {
+				return
+			}
+			change, err := managedBlockChange([]byte(tt.existing), "Generated\n")
+			if err != nil {
+				t.Fatalf("managedBlockChange returned error: %v", err)
+			}
+			if change != tt.wantChange {
+				t.Errorf("managedBlockChange() = %q, want %q", change, tt.wantChange)
+			}
+		})
+	}
+}
+
+// TestPipelineManagedBlock verifies that managed-block files keep the hand-written parts of
+// existing files and that dry runs report block-level changes.
+func TestPipelineManagedBlock(t *testing.T) {
+	existing := "# Project\nHand-written notes\n"
+	mockFS := newMockFileSystem([]string{"/output/CLAUDE.md"})
+	mockFS.SetFileContent("/output/CLAUDE.md", existing)
+
+	output := newMockOutputWriter()
+	pipeline := &Pipeline{
+		Task:          config.Task{Name: "test-task", Type: "memory"},
+		AbsInputRoot:  "/input",
+		AbsOutputDirs: []string{"/output"},
+		DryRun:        true,
+		fs:            mockFS,
+		logger:        zap.NewNop(),
+		output:        output,
+	}
+	files := []ProcessedFile{{relPath: "CLAUDE.md", Content: "Generated", managed: true}}
+
+	if err := pipeline.writeOutputFiles(files); err != nil {
+		t.Fatalf("writeOutputFiles returned error: %v", err)
+	}
+	found := false
+	for _, msg := range output.messages {
+		if strings.Contains(msg, "[MODIFY]") && strings.Contains(msg, "managed block added") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected dry run to report an added managed block, got %v", output.messages)
+	}
+	if _, ok := mockFS.writtenFiles["/output/CLAUDE.md"]; ok {
+		t.Errorf("dry run should not write files")
+	}
+
+	pipeline.DryRun = false
+	if err := pipeline.writeOutputFiles(files); err != nil {
+		t.Fatalf("writeOutputFiles returned error: %v", err)
+	}
+	want := existing + "\n<!-- agent-sync:begin -->\nGenerated\n<!-- agent-sync:end -->\n"
+	if got := string(mockFS.writtenFiles["/output/CLAUDE.md"]); got != want {
+		t.Errorf("written content =\n%q\nwant\n%q", got, want)
+	}
+}
+
+// TestPipelineManagedBlock_Frontmatter verifies that outputs starting with frontmatter
+// are rejected in managed-block mode, since the frontmatter must stay at the top of the file.
+func TestPipelineManagedBlock_Frontmatter(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "memories/style.md", "---\ncursor:\n  alwaysApply: true\n---\nUse tabs\n")
+
+	task := config.Task{
+		Name:    "memories",
+		Type:    "memory",
+		Inputs:  []string{"memories/*.md"},
+		Outputs: []config.Output{{Agent: "cursor", Mode: config.OutputModeManagedBlock}},
+	}
+	pipeline, err := NewPipeline(task, dir, []string{filepath.Join(dir, "out")}, false, false, true, zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewPipeline returned error: %v", err)
+	}
+	err = pipeline.Execute()
+	if err == nil || !strings.Contains(err.Error(), "starts with frontmatter") {
+		t.Errorf("expected frontmatter error, got %v", err)
+	}
+}
+
+func TestHasFrontmatter(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"---\ndescription: a\n---\nbody\n", true},
+		{"---\r\ndescription: a\r\n---\r\nbody\r\n", true},
+		{"# Title\n\n---\n", false},
+		{"----\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := hasFrontmatter(tt.content); got != tt.want {
+			t.Errorf("hasFrontmatter(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/uphy/agent-sync/internal/config"
 	"github.com/uphy/agent-sync/internal/log"
+	"github.com/uphy/agent-sync/internal/model"
+	"github.com/uphy/agent-sync/internal/template"
 	"github.com/uphy/agent-sync/internal/util"
 	"go.uber.org/zap"
 )
@@ -19,6 +22,15 @@ type Manager struct {
 	force        bool
 	logger       *zap.Logger
 	output       log.OutputWriter
+
+	// showDiff makes dry runs print a unified diff for every file that would change
+	showDiff bool
+	// changes collects the files the last dry run found would be created, modified or deleted
+	changes []FileChange
+	// checking makes stale files that are kept instead of deleted count as changes
+	checking bool
+	// projectOnly skips user-level tasks and the user home directory
+	projectOnly bool
 }
 
 // NewManager creates a new Manager by loading configuration from the given path.
@@ -57,32 +69,70 @@ func NewManager(cfgPath string, logger *zap.Logger, output log.OutputWriter) (*M
 // Apply executes the apply pipeline for all projects and user scope.
 func (m *Manager) Apply(dryRun, force bool) error {
 	m.force = force
+	m.changes = nil
 
 	m.logger.Info("Starting apply process",
 		zap.Bool("dryRun", dryRun),
 		zap.Bool("force", force))
 
 	// Print clear dry run message at the beginning
-	if dryRun && m.output != nil {
+	if dryRun && !m.showDiff && m.output != nil {
 		m.output.PrintProgress("DRY RUN MODE: No files will actually be written")
 	}
 
-	// Process project-level tasks
-	for name, proj := range m.cfg.Projects {
-		// Resolve absolute paths for input root and output directories
-		absInputRoot := m.absConfigDir
-		absOutputDirs := proj.OutputDirs
-		for i, outputDir := range absOutputDirs {
-			if filepath.IsAbs(outputDir) {
-				continue
+	// plan collects the files generated for each output directory, used to prune stale outputs
+	plan := make(map[string]*Manifest)
+	for _, dir := range m.outputDirs() {
+		plan[dir] = &Manifest{Version: manifestVersion}
+	}
+
+	if err := m.runTasks(plan, dryRun); err != nil {
+		// Record the files written before the failure, so that later runs still know them
+		if !dryRun {
+			if saveErr := m.saveWritten(plan); saveErr != nil {
+				m.logger.Error("Failed to record written files in manifests", zap.Error(saveErr))
 			}
-			outputDir = filepath.Join(absInputRoot, outputDir)
-			var err error
-			outputDir, err = filepath.Abs(outputDir)
-			if err != nil {
-				return fmt.Errorf("failed to resolve absolute path for output directory %s: %w", outputDir, err)
+		}
+		return err
+	}
+
+	// Remove the outputs of previous runs that are no longer generated
+	return m.syncManifests(plan, dryRun)
+}
+
+// runTasks executes the pipelines of all project and user-level tasks, adding the files
+// they write (or plan in a dry run) to plan. A failing pipeline adds the files it wrote before failing.
+func (m *Manager) runTasks(plan map[string]*Manifest, dryRun bool) error {
+	addToPlan := func(manifests map[string]*Manifest) {
+		for dir, manifest := range manifests {
+			if plan[dir] == nil {
+				plan[dir] = &Manifest{Version: manifestVersion}
 			}
-			absOutputDirs[i] = outputDir
+			for _, entry := range manifest.Files {
+				plan[dir].Add(entry)
+			}
+		}
+	}
+
+	// Index the commands and modes of all tasks so that templates can reference them
+	catalog, err := m.buildCatalog()
+	if err != nil {
+		return err
+	}
+
+	// Process project-level tasks in a stable order
+	projectNames := make([]string, 0, len(m.cfg.Projects))
+	for name := range m.cfg.Projects {
+		projectNames = append(projectNames, name)
+	}
+	sort.Strings(projectNames)
+	for _, name := range projectNames {
+		proj := m.cfg.Projects[name]
+		// Resolve absolute paths for input root and output directories
+		absInputRoot := m.absConfigDir
+		absOutputDirs, err := m.projectOutputDirs(proj)
+		if err != nil {
+			return err
 		}
 
 		// Always use config directory as the project root
@@ -108,7 +158,14 @@ func (m *Manager) Apply(dryRun, force bool) error {
 			if err != nil {
 				return fmt.Errorf("failed to create pipeline for project %s task %s: %w", name, task.Name, err)
 			}
-			if err := pipeline.Execute(); err != nil {
+			pipeline.written = plan
+			pipeline.ShowDiff = m.showDiff
+			pipeline.ProjectName = name
+			pipeline.Vars = config.MergeVars(m.cfg.Vars, proj.Vars)
+			pipeline.Catalog = catalog
+			err = pipeline.Execute()
+			addToPlan(pipeline.Manifests())
+			if err != nil {
 				m.logger.Error("Project task execution failed",
 					zap.String("project", name),
 					zap.Error(err))
@@ -119,6 +176,7 @@ func (m *Manager) Apply(dryRun, force bool) error {
 
 				return fmt.Errorf("project %s task execution failed: %w", name, err)
 			}
+			m.changes = append(m.changes, pipeline.Changes()...)
 		}
 
 		if m.output != nil {
@@ -127,22 +185,14 @@ func (m *Manager) Apply(dryRun, force bool) error {
 	}
 
 	// Process user-level tasks
-	for _, task := range m.cfg.User.Tasks {
-		// Use User.Home setting when specified, otherwise fallback to system home directory
-		var home string
-		if m.cfg.User.Home != "" {
-			home = m.cfg.User.Home
-		} else {
-			var err error
-			home, err = os.UserHomeDir()
-			if err != nil {
-				home = ""
-			}
-		}
-
-		absHome, err := filepath.Abs(home)
+	userTasks := m.cfg.User.Tasks
+	if m.projectOnly {
+		userTasks = nil
+	}
+	for _, task := range userTasks {
+		absHome, err := m.userHomeDir()
 		if err != nil {
-			return fmt.Errorf("failed to resolve user home directory: %w", err)
+			return err
 		}
 
 		m.logger.Info("Processing user-level task",
@@ -159,7 +209,13 @@ func (m *Manager) Apply(dryRun, force bool) error {
 		if err != nil {
 			return fmt.Errorf("failed to create pipeline for user task %s: %w", task.Name, err)
 		}
-		if err := pipeline.Execute(); err != nil {
+		pipeline.written = plan
+		pipeline.ShowDiff = m.showDiff
+		pipeline.Vars = m.cfg.Vars
+		pipeline.Catalog = catalog
+		err = pipeline.Execute()
+		addToPlan(pipeline.Manifests())
+		if err != nil {
 			m.logger.Error("User task execution failed", zap.Error(err))
 
 			if m.output != nil {
@@ -168,11 +224,342 @@ func (m *Manager) Apply(dryRun, force bool) error {
 
 			return fmt.Errorf("user task execution failed: %w", err)
 		}
+		m.changes = append(m.changes, pipeline.Changes()...)
 
 		if m.output != nil {
 			m.output.PrintSuccess(fmt.Sprintf("User task %s processed successfully", task.Name))
 		}
 	}
+	return nil
+}
+
+// Diff runs the apply planning phase without writing files and prints a unified diff for every
+// file that would be created, modified or deleted. It returns the files that would change.
+func (m *Manager) Diff() ([]FileChange, error) {
+	m.showDiff = true
+	defer func() { m.showDiff = false }()
+
+	if err := m.Apply(true, false); err != nil {
+		return nil, err
+	}
+	return m.changes, nil
+}
+
+// Check runs the apply planning phase without writing files or printing the plan, and returns the
+// files whose content on disk differs from the generated content, including stale generated files.
+// With projectOnly, user-level tasks are skipped, so that the result does not depend on the home directory.
+func (m *Manager) Check(projectOnly bool) ([]FileChange, error) {
+	output := m.output
+	m.output = nil
+	m.checking = true
+	m.projectOnly = projectOnly
+	defer func() {
+		m.output = output
+		m.checking = false
+		m.projectOnly = false
+	}()
 
+	if err := m.Apply(true, false); err != nil {
+		return nil, err
+	}
+	return m.changes, nil
+}
+
+// Clean removes all files generated by this configuration, as recorded in the manifests of its output directories.
+func (m *Manager) Clean(dryRun, force bool) error {
+	m.force = force
+
+	m.logger.Info("Starting clean process",
+		zap.Bool("dryRun", dryRun),
+		zap.Bool("force", force))
+
+	if dryRun && m.output != nil {
+		m.output.PrintProgress("DRY RUN MODE: No files will actually be deleted")
+	}
+
+	// An empty plan makes every file generated by this configuration stale
+	plan := make(map[string]*Manifest)
+	for _, dir := range m.outputDirs() {
+		plan[dir] = &Manifest{Version: manifestVersion}
+	}
+	if err := m.syncManifests(plan, dryRun); err != nil {
+		return err
+	}
+
+	if m.output != nil && !dryRun {
+		m.output.PrintSuccess("Generated files cleaned successfully")
+	}
 	return nil
 }
+
+// syncManifests updates the manifest of each output directory to the planned files and
+// removes the files recorded by a previous run of this configuration that are no longer planned.
+// Entries recorded by other configurations sharing an output directory are left alone.
+func (m *Manager) syncManifests(plan map[string]*Manifest, dryRun bool) error {
+	dirs := make([]string, 0, len(plan))
+	for dir := range plan {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	fs := &util.RealFileSystem{}
+	for _, dir := range dirs {
+		previous, err := LoadManifest(fs, dir)
+		if err != nil {
+			return err
+		}
+
+		configRef := manifestConfigRef(dir, m.absConfigDir)
+		next := &Manifest{Version: manifestVersion}
+		for _, entry := range previous.Files {
+			if entry.Config != configRef {
+				next.Add(entry)
+			}
+		}
+		for _, entry := range plan[dir].Files {
+			entry.Config = configRef
+			next.Add(entry)
+		}
+
+		var stale []ManifestEntry
+		for _, entry := range previous.Files {
+			if entry.Config != configRef {
+				continue
+			}
+			if _, ok := plan[dir].Get(entry.Path); ok {
+				continue
+			}
+			if !fs.FileExists(filepath.Join(dir, filepath.FromSlash(entry.Path))) {
+				m.logger.Debug("Stale file already removed", zap.String("path", entry.Path))
+				continue
+			}
+			stale = append(stale, entry)
+		}
+		if len(stale) > 0 {
+			m.print(fmt.Sprintf("\nStale files in %s:", dir))
+		}
+		for _, entry := range stale {
+			keep, err := m.removeStaleFile(fs, dir, entry, dryRun)
+			if err != nil {
+				return err
+			}
+			if keep {
+				next.Add(entry)
+			}
+		}
+
+		if dryRun {
+			continue
+		}
+		if err := next.Save(fs, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveWritten adds the files written by a failed apply to the manifests of their output
+// directories. Unlike syncManifests it keeps all previous entries and removes nothing,
+// since the tasks after the failure did not run.
+func (m *Manager) saveWritten(plan map[string]*Manifest) error {
+	dirs := make([]string, 0, len(plan))
+	for dir := range plan {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	fs := &util.RealFileSystem{}
+	for _, dir := range dirs {
+		if len(plan[dir].Files) == 0 {
+			continue
+		}
+		manifest, err := LoadManifest(fs, dir)
+		if err != nil {
+			return err
+		}
+		configRef := manifestConfigRef(dir, m.absConfigDir)
+		for _, entry := range plan[dir].Files {
+			entry.Config = configRef
+			manifest.Add(entry)
+		}
+		if err := manifest.Save(fs, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStaleFile deletes a generated file that is no longer planned.
+// Files that also hold hand-written content, or that were modified after they were generated,
+// are reported instead of deleted; keep reports whether the entry stays in the manifest.
+func (m *Manager) removeStaleFile(fs util.FileSystem, absOutputDir string, entry ManifestEntry, dryRun bool) (keep bool, err error) {
+	absPath := filepath.Join(absOutputDir, filepath.FromSlash(entry.Path))
+	if entry.Shared {
+		m.print(fmt.Sprintf("  [KEEP] %s (shared with content not generated by agent-sync; remove the generated content manually)", absPath))
+		if m.checking {
+			m.changes = append(m.changes, FileChange{Path: absPath, Kind: ChangeDelete})
+		}
+		return false, nil
+	}
+
+	content, err := fs.ReadFile(absPath)
+	if err != nil {
+		return false, fmt.Errorf("read stale file %s: %w", absPath, err)
+	}
+	if hashContent(content) != entry.Hash && !m.force {
+		m.print(fmt.Sprintf("  [MODIFIED] %s (changed since it was generated; use --force to delete)", absPath))
+		if m.checking {
+			m.changes = append(m.changes, FileChange{Path: absPath, Kind: ChangeDelete})
+		}
+		return true, nil
+	}
+
+	if dryRun {
+		m.logger.Info("[DRY RUN] Would delete stale file", zap.String("path", absPath), zap.String("task", entry.Task))
+		m.print(fmt.Sprintf("  [DELETE] %s", absPath))
+		m.changes = append(m.changes, FileChange{Path: absPath, Kind: ChangeDelete})
+		if m.showDiff && m.output != nil {
+			m.output.PrintDiff(util.UnifiedDiff(absPath, "/dev/null", string(content), ""))
+		}
+		return false, nil
+	}
+	if err := fs.RemoveFile(absPath); err != nil {
+		return false, fmt.Errorf("delete stale file %s: %w", absPath, err)
+	}
+	m.logger.Info("Deleted stale file", zap.String("path", absPath), zap.String("task", entry.Task))
+	m.print(fmt.Sprintf("  [DELETE] %s", absPath))
+	if err := removeEmptyParents(fs, absOutputDir, absPath); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// removeEmptyParents removes the directories containing a deleted file that became empty,
+// up to but excluding the output directory
+func removeEmptyParents(fs util.FileSystem, absOutputDir, absPath string) error {
+	for dir := filepath.Dir(absPath); dir != absOutputDir; dir = filepath.Dir(dir) {
+		if isSub, err := util.IsSub(absOutputDir, dir); err != nil || !isSub {
+			return nil
+		}
+		removed, err := fs.RemoveEmptyDir(dir)
+		if err != nil {
+			return fmt.Errorf("remove empty directory %s: %w", dir, err)
+		}
+		if !removed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// buildCatalog parses the inputs of all command and mode tasks, at both the project and user level.
+// Tasks whose inputs cannot be resolved are skipped here and reported when they are processed.
+func (m *Manager) buildCatalog() (*template.Catalog, error) {
+	catalog := template.NewCatalog()
+	fs := &util.RealFileSystem{}
+
+	tasks := append([]config.Task{}, m.cfg.User.Tasks...)
+	for _, proj := range m.cfg.Projects {
+		tasks = append(tasks, proj.Tasks...)
+	}
+	for _, task := range tasks {
+		if task.Type != "command" && task.Type != "mode" {
+			continue
+		}
+		inputs, err := fs.GlobWithExcludes(task.Inputs, m.absConfigDir)
+		if err != nil {
+			continue
+		}
+		for _, input := range inputs {
+			absPath := input
+			if !filepath.IsAbs(absPath) {
+				absPath = util.JoinPath(m.absConfigDir, input)
+			}
+			content, err := fs.ReadFile(absPath)
+			if err != nil {
+				return nil, fmt.Errorf("read input file %s: %w", absPath, err)
+			}
+			if task.Type == "command" {
+				command, err := model.ParseCommand(absPath, content)
+				if err != nil {
+					return nil, fmt.Errorf("parse command %s: %w", absPath, err)
+				}
+				catalog.AddCommand(*command)
+			} else {
+				mode, err := model.ParseMode(absPath, content)
+				if err != nil {
+					return nil, fmt.Errorf("parse mode %s: %w", absPath, err)
+				}
+				catalog.AddMode(*mode)
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// print writes a message to the output writer, if any
+func (m *Manager) print(msg string) {
+	if m.output != nil {
+		m.output.Print(msg)
+	}
+}
+
+// outputDirs returns the absolute output directories of all projects and, unless only projects
+// are processed, the user home directory
+func (m *Manager) outputDirs() []string {
+	var dirs []string
+	for name, proj := range m.cfg.Projects {
+		projectDirs, err := m.projectOutputDirs(proj)
+		if err != nil {
+			m.logger.Warn("Skipping output directories of project", zap.String("project", name), zap.Error(err))
+			continue
+		}
+		dirs = append(dirs, projectDirs...)
+	}
+	if m.projectOnly {
+		return dirs
+	}
+	if home, err := m.userHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	return dirs
+}
+
+// projectOutputDirs resolves the output directories of a project relative to the config directory
+func (m *Manager) projectOutputDirs(proj config.Project) ([]string, error) {
+	absOutputDirs := make([]string, len(proj.OutputDirs))
+	for i, outputDir := range proj.OutputDirs {
+		if filepath.IsAbs(outputDir) {
+			absOutputDirs[i] = outputDir
+			continue
+		}
+		outputDir = filepath.Join(m.absConfigDir, outputDir)
+		absOutputDir, err := filepath.Abs(outputDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve absolute path for output directory %s: %w", outputDir, err)
+		}
+		absOutputDirs[i] = absOutputDir
+	}
+	return absOutputDirs, nil
+}
+
+// userHomeDir returns the output directory of user-level tasks.
+// It uses the User.Home setting when specified, otherwise falls back to the system home directory.
+func (m *Manager) userHomeDir() (string, error) {
+	var home string
+	if m.cfg.User.Home != "" {
+		home = m.cfg.User.Home
+	} else {
+		var err error
+		home, err = os.UserHomeDir()
+		if err != nil {
+			home = ""
+		}
+	}
+
+	absHome, err := filepath.Abs(home)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user home directory: %w", err)
+	}
+	return absHome, nil
+}
@@ -0,0 +1,324 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/log"
+	"go.uber.org/zap"
+)
+
+// writeTestFile writes a file relative to dir, creating parent directories
+func writeTestFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeCommandConfig writes a config generating Claude commands from cmds/*.md into out/
+func writeCommandConfig(t *testing.T, dir string) string {
+	t.Helper()
+	writeTestFile(t, dir, "agent-sync.yml", `configVersion: "1.0"
+projects:
+  p:
+    outputDirs: [out]
+    tasks:
+      - type: command
+        inputs: [cmds/*.md]
+        outputs:
+          - agent: claude
+user:
+  home: `+filepath.Join(dir, "home")+`
+  tasks: []
+`)
+	return filepath.Join(dir, "agent-sync.yml")
+}
+
+func TestManagerDiff(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeCommandConfig(t, dir)
+	writeTestFile(t, dir, "cmds/a.md", "---\ndescription: a\n---\nA\n")
+	writeTestFile(t, dir, "cmds/b.md", "---\ndescription: b\n---\nB\n")
+
+	diff := func() ([]FileChange, *log.TestOutput) {
+		t.Helper()
+		output := log.NewTestOutput(false)
+		mgr, err := NewManager(cfgPath, zap.NewNop(), output)
+		if err != nil {
+			t.Fatalf("NewManager returned error: %v", err)
+		}
+		changes, err := mgr.Diff()
+		if err != nil {
+			t.Fatalf("Diff returned error: %v", err)
+		}
+		return changes, output
+	}
+
+	// Nothing generated yet: both files would be created, and nothing is written
+	changes, output := diff()
+	if len(changes) != 2 || changes[0].Kind != ChangeCreate || len(output.Diffs) != 2 {
+		t.Fatalf("changes = %v, diffs = %v; want 2 created files", changes, output.Diffs)
+	}
+	if !strings.HasPrefix(output.Diffs[0], "--- /dev/null\n") {
+		t.Errorf("expected a creation diff, got\n%s", output.Diffs[0])
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out")); !os.IsNotExist(err) {
+		t.Fatalf("diff should not write files")
+	}
+
+	mgr, err := NewManager(cfgPath, zap.NewNop(), log.NewTestOutput(false))
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	if err := mgr.Apply(false, false); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if changes, output := diff(); len(changes) != 0 || len(output.Diffs) != 0 {
+		t.Fatalf("changes = %v, diffs = %v; want no differences after apply", changes, output.Diffs)
+	}
+
+	// A modified source and a removed source show up as a modification and a deletion
+	writeTestFile(t, dir, "cmds/a.md", "---\ndescription: a\n---\nA2\n")
+	if err := os.Remove(filepath.Join(dir, "cmds/b.md")); err != nil {
+		t.Fatal(err)
+	}
+	changes, output = diff()
+	if len(changes) != 2 || changes[0].Kind != ChangeModify || changes[1].Kind != ChangeDelete || len(output.Diffs) != 2 {
+		t.Fatalf("changes = %v, diffs = %v; want a modified and a deleted file", changes, output.Diffs)
+	}
+	if !strings.Contains(output.Diffs[0], "-A\n\\ No newline at end of file\n+A2\n") {
+		t.Errorf("expected a modification diff, got\n%s", output.Diffs[0])
+	}
+	if !strings.Contains(output.Diffs[1], "+++ /dev/null\n") {
+		t.Errorf("expected a deletion diff, got\n%s", output.Diffs[1])
+	}
+}
+
+func TestManagerCheck(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeCommandConfig(t, dir)
+	writeTestFile(t, dir, "cmds/a.md", "---\ndescription: a\n---\nA\n")
+	writeTestFile(t, dir, "cmds/b.md", "---\ndescription: b\n---\nB\n")
+
+	output := log.NewTestOutput(false)
+	mgr, err := NewManager(cfgPath, zap.NewNop(), output)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	if err := mgr.Apply(false, false); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	changes, err := mgr.Check(false)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("changes = %v; want none after apply", changes)
+	}
+
+	// Edit generated files, remove a source and add a new one.
+	// The edited stale file is kept by apply, but still reported as orphaned.
+	outDir := filepath.Join(dir, "out", ".claude", "commands")
+	writeTestFile(t, outDir, "a.md", "edited\n")
+	writeTestFile(t, outDir, "b.md", "edited\n")
+	if err := os.Remove(filepath.Join(dir, "cmds/b.md")); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "cmds/c.md", "---\ndescription: c\n---\nC\n")
+
+	output.Messages = nil
+	changes, err = mgr.Check(false)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	want := []FileChange{
+		{Path: filepath.Join(outDir, "a.md"), Kind: ChangeModify},
+		{Path: filepath.Join(outDir, "c.md"), Kind: ChangeCreate},
+		{Path: filepath.Join(outDir, "b.md"), Kind: ChangeDelete},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+	if len(output.Messages) != 0 {
+		t.Errorf("check should not print the plan, got %v", output.Messages)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "c.md")); !os.IsNotExist(err) {
+		t.Errorf("check should not write files")
+	}
+}
+
+func TestManagerCheck_ProjectOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "agent-sync.yml", `configVersion: "1.0"
+projects:
+  p:
+    outputDirs: [out]
+    tasks:
+      - type: memory
+        inputs: [memories/*.md]
+        outputs:
+          - agent: claude
+user:
+  home: `+filepath.Join(dir, "home")+`
+  tasks:
+    - type: memory
+      inputs: [memories/*.md]
+      outputs:
+        - agent: claude
+`)
+	writeTestFile(t, dir, "memories/m.md", "memory\n")
+
+	mgr, err := NewManager(filepath.Join(dir, "agent-sync.yml"), zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	changes, err := mgr.Check(false)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("changes = %v; want the project and user memory", changes)
+	}
+
+	changes, err = mgr.Check(true)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	want := []FileChange{{Path: filepath.Join(dir, "out", "CLAUDE.md"), Kind: ChangeCreate}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestManagerTemplateData(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "agent-sync.yml", `configVersion: "1.0"
+vars:
+  repo: agent-sync
+  owner: root
+projects:
+  web:
+    outputDirs: [out]
+    vars:
+      owner: project
+    tasks:
+      - name: docs
+        type: command
+        inputs: [cmds/*.md]
+        vars:
+          owner: task
+        outputs:
+          - agent: claude
+            vars:
+              owner: output
+user:
+  home: `+filepath.Join(dir, "home")+`
+  tasks:
+    - type: memory
+      inputs: [memories/*.md]
+      outputs:
+        - agent: claude
+`)
+	writeTestFile(t, dir, "cmds/a.md", "---\ndescription: a\n---\n{{ .Vars.repo }} {{ .Vars.owner }} {{ .Project.Name }} {{ .Task.Name }} {{ .Scope }} {{ .Agent }} {{ .OutputPath }}\n")
+	writeTestFile(t, dir, "memories/m.md", "{{ .Vars.owner }} [{{ .Project.Name }}] {{ .Task.Name }} {{ .Scope }} {{ .OutputPath }}\n")
+
+	mgr, err := NewManager(filepath.Join(dir, "agent-sync.yml"), zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	if err := mgr.Apply(false, true); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"out/.claude/commands/a.md", "agent-sync output web docs project claude .claude/commands/a.md"},
+		{"home/.claude/CLAUDE.md", "root [] user-memory user .claude/CLAUDE.md"},
+	}
+	for _, tt := range tests {
+		content, err := os.ReadFile(filepath.Join(dir, tt.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), tt.want) {
+			t.Errorf("%s = %q, want it to contain %q", tt.path, content, tt.want)
+		}
+	}
+}
+
+// TestManagerCommandAndModeRefs verifies that templates can reference commands and modes defined by
+// any task of the configuration, and that a reference to an undefined command fails the apply.
+func TestManagerCommandAndModeRefs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "agent-sync.yml", `configVersion: "1.0"
+projects:
+  web:
+    outputDirs: [out]
+    tasks:
+      - type: memory
+        inputs: [memories/*.md]
+        outputs:
+          - agent: claude
+          - agent: codex
+      - type: command
+        inputs: [cmds/*.md]
+        outputs:
+          - agent: claude
+          - agent: codex
+user:
+  home: `+filepath.Join(dir, "home")+`
+  tasks:
+    - type: mode
+      inputs: [modes/*.md]
+      outputs:
+        - agent: claude
+`)
+	writeTestFile(t, dir, "cmds/deploy.md", "---\ndescription: deploy\n---\nDeploy\n")
+	writeTestFile(t, dir, "modes/reviewer.md", "---\nclaude:\n  name: code-reviewer\n  description: Reviews code\n---\nReview\n")
+	writeTestFile(t, dir, "memories/m.md", "Run {{ command \"deploy\" }}.{{ if isClaude }} Ask {{ mode \"reviewer\" }}.{{ end }}\n")
+
+	mgr, err := NewManager(filepath.Join(dir, "agent-sync.yml"), zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	if err := mgr.Apply(false, true); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"out/CLAUDE.md", "Run /deploy. Ask @agent-code-reviewer."},
+		{"out/AGENTS.md", "Run /prompts:deploy."},
+	}
+	for _, tt := range tests {
+		content, err := os.ReadFile(filepath.Join(dir, tt.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), tt.want) {
+			t.Errorf("%s = %q, want it to contain %q", tt.path, content, tt.want)
+		}
+	}
+
+	writeTestFile(t, dir, "memories/m.md", "Run {{ command \"release\" }}.\n")
+	mgr, err = NewManager(filepath.Join(dir, "agent-sync.yml"), zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	err = mgr.Apply(false, true)
+	if err == nil || !strings.Contains(err.Error(), `command "release" is not defined in the configuration`) {
+		t.Errorf("expected an undefined command error, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/uphy/agent-sync/internal/util"
+)
+
+// ManifestPath is the location of the manifest relative to an output directory
+const ManifestPath = ".agent-sync/manifest.json"
+
+// manifestVersion is the current version of the manifest format
+const manifestVersion = 1
+
+// Manifest records the files agent-sync has written to an output directory,
+// so that outputs which are no longer generated can be found and removed.
+type Manifest struct {
+	Version int             `json:"version"`
+	Files   []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry describes a single generated file
+type ManifestEntry struct {
+	// Path is the slash-separated path of the file relative to the output directory
+	Path string `json:"path"`
+	// Hash is the hash of the content written by agent-sync ("sha256:<hex>");
+	// for managed-block outputs it covers the managed block only
+	Hash string `json:"hash"`
+	// Task is the name of the task that generated the file
+	Task string `json:"task"`
+	// Config is the slash-separated directory of the configuration that generated the file,
+	// relative to the output directory. Output directories such as the user home directory
+	// can be shared by several configurations.
+	Config string `json:"config"`
+	// Shared is set for files that also hold content agent-sync does not generate
+	// (merged or managed-block outputs); they are never deleted
+	Shared bool `json:"shared,omitempty"`
+	// Owned maps the names of the tasks merging into a shared file to what each of them
+	// generated there (e.g. JSON pointers), so that content no longer generated can be removed
+	Owned map[string][]string `json:"owned,omitempty"`
+}
+
+// LoadManifest reads the manifest of an output directory.
+// A missing manifest results in an empty manifest.
+func LoadManifest(fs util.FileSystem, absOutputDir string) (*Manifest, error) {
+	path := filepath.Join(absOutputDir, ManifestPath)
+	if !fs.FileExists(path) {
+		return &Manifest{Version: manifestVersion}, nil
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest %s: %w", path, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+	if m.Version > manifestVersion {
+		return nil, fmt.Errorf("manifest %s has unsupported version %d", path, m.Version)
+	}
+	return &m, nil
+}
+
+// Save writes the manifest to an output directory, or removes the manifest file when it is empty
+func (m *Manifest) Save(fs util.FileSystem, absOutputDir string) error {
+	path := filepath.Join(absOutputDir, ManifestPath)
+	if len(m.Files) == 0 {
+		var notFound *util.ErrFileNotFound
+		if err := fs.RemoveFile(path); err != nil && !errors.As(err, &notFound) {
+			return fmt.Errorf("remove manifest %s: %w", path, err)
+		}
+		if _, err := fs.RemoveEmptyDir(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("remove manifest directory: %w", err)
+		}
+		return nil
+	}
+
+	m.Version = manifestVersion
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := fs.WriteFile(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("write manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// Get returns the entry for a path
+func (m *Manifest) Get(path string) (ManifestEntry, bool) {
+	for _, e := range m.Files {
+		if e.Path == path {
+			return e, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+// Add records an entry, replacing any entry with the same path.
+// A file written by several tasks stays shared once any of them shares it,
+// and keeps what each of them owns.
+func (m *Manifest) Add(entry ManifestEntry) {
+	for i, e := range m.Files {
+		if e.Path == entry.Path {
+			entry.Shared = entry.Shared || e.Shared
+			entry.Owned = mergeOwned(e.Owned, entry.Owned)
+			m.Files[i] = entry
+			return
+		}
+	}
+	m.Files = append(m.Files, entry)
+}
+
+// mergeOwned combines the owned content of two entries for the same file; tasks in next win
+func mergeOwned(previous, next map[string][]string) map[string][]string {
+	if len(previous) == 0 {
+		return next
+	}
+	merged := make(map[string][]string, len(previous)+len(next))
+	for task, owned := range previous {
+		merged[task] = owned
+	}
+	for task, owned := range next {
+		merged[task] = owned
+	}
+	return merged
+}
+
+// manifestConfigRef returns the reference to a configuration directory recorded in the manifest of an output directory
+func manifestConfigRef(absOutputDir, absConfigDir string) string {
+	rel, err := filepath.Rel(absOutputDir, absConfigDir)
+	if err != nil {
+		return filepath.ToSlash(absConfigDir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// hashContent returns the manifest hash of file content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
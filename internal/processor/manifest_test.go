@@ -0,0 +1,185 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/util"
+	"go.uber.org/zap"
+)
+
+func TestManifestSaveLoad(t *testing.T) {
+	fs := &util.RealFileSystem{}
+	dir := t.TempDir()
+
+	m, err := LoadManifest(fs, dir)
+	if err != nil {
+		t.Fatalf("LoadManifest returned error: %v", err)
+	}
+	if len(m.Files) != 0 {
+		t.Fatalf("expected empty manifest, got %+v", m.Files)
+	}
+
+	m.Add(ManifestEntry{Path: "b.md", Hash: hashContent([]byte("b")), Task: "t1"})
+	m.Add(ManifestEntry{Path: "a.md", Hash: hashContent([]byte("a")), Task: "t1", Shared: true})
+	// Re-adding a path replaces the entry but keeps it shared, and keeps what other tasks own
+	m.Add(ManifestEntry{Path: "a.md", Hash: hashContent([]byte("a1")), Task: "t1", Owned: map[string][]string{"t1": {"/a"}}})
+	m.Add(ManifestEntry{Path: "a.md", Hash: hashContent([]byte("a2")), Task: "t2", Owned: map[string][]string{"t2": {"/b"}}})
+	if err := m.Save(fs, dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := LoadManifest(fs, dir)
+	if err != nil {
+		t.Fatalf("LoadManifest returned error: %v", err)
+	}
+	if len(loaded.Files) != 2 || loaded.Files[0].Path != "a.md" || loaded.Files[1].Path != "b.md" {
+		t.Fatalf("unexpected entries: %+v", loaded.Files)
+	}
+	if e := loaded.Files[0]; e.Task != "t2" || !e.Shared || e.Hash != hashContent([]byte("a2")) {
+		t.Errorf("unexpected replaced entry: %+v", e)
+	}
+	if owned := loaded.Files[0].Owned; len(owned) != 2 || owned["t1"][0] != "/a" || owned["t2"][0] != "/b" {
+		t.Errorf("unexpected owned content: %+v", owned)
+	}
+
+	// An empty manifest removes the manifest file
+	if err := (&Manifest{}).Save(fs, dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if fs.FileExists(filepath.Join(dir, ManifestPath)) {
+		t.Errorf("expected empty manifest to be removed")
+	}
+}
+
+// TestManagerPrunesStaleOutputs verifies that apply removes outputs that are no longer generated,
+// keeps modified ones, and that clean removes the remaining generated files.
+func TestManagerPrunesStaleOutputs(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeCommandConfig(t, dir)
+	writeFile := func(rel, content string) {
+		t.Helper()
+		writeTestFile(t, dir, rel, content)
+	}
+	writeFile("cmds/a.md", "---\ndescription: a\n---\nA\n")
+	writeFile("cmds/b.md", "---\ndescription: b\n---\nB\n")
+	writeFile("cmds/c.md", "---\ndescription: c\n---\nC\n")
+
+	apply := func(dryRun bool) {
+		t.Helper()
+		mgr, err := NewManager(cfgPath, zap.NewNop(), nil)
+		if err != nil {
+			t.Fatalf("NewManager returned error: %v", err)
+		}
+		if err := mgr.Apply(dryRun, false); err != nil {
+			t.Fatalf("Apply returned error: %v", err)
+		}
+	}
+	exists := func(rel string) bool {
+		_, err := os.Stat(filepath.Join(dir, "out", rel))
+		return err == nil
+	}
+
+	apply(false)
+	for _, name := range []string{"a.md", "b.md", "c.md"} {
+		if !exists(".claude/commands/" + name) {
+			t.Fatalf("expected %s to be generated", name)
+		}
+	}
+
+	// b is removed from the sources and c is edited by hand
+	if err := os.Remove(filepath.Join(dir, "cmds/b.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "cmds/c.md")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile("out/.claude/commands/c.md", "edited\n")
+
+	apply(true)
+	if !exists(".claude/commands/b.md") {
+		t.Fatalf("dry run should not delete stale files")
+	}
+
+	apply(false)
+	if exists(".claude/commands/b.md") {
+		t.Errorf("expected stale b.md to be deleted")
+	}
+	if !exists(".claude/commands/c.md") {
+		t.Errorf("expected modified c.md to be kept")
+	}
+	manifest, err := LoadManifest(&util.RealFileSystem{}, filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("LoadManifest returned error: %v", err)
+	}
+	if _, ok := manifest.Get(".claude/commands/c.md"); !ok {
+		t.Errorf("expected modified file to stay in the manifest, got %+v", manifest.Files)
+	}
+
+	mgr, err := NewManager(cfgPath, zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	if err := mgr.Clean(false, false); err != nil {
+		t.Fatalf("Clean returned error: %v", err)
+	}
+	if exists(".claude/commands/a.md") {
+		t.Errorf("expected clean to delete a.md")
+	}
+	if !exists(".claude/commands/c.md") {
+		t.Errorf("expected clean to keep modified c.md without force")
+	}
+
+	// Directories emptied by deleting stale files are removed as well
+	if err := mgr.Clean(false, true); err != nil {
+		t.Fatalf("Clean returned error: %v", err)
+	}
+	for _, rel := range []string{".claude", ".agent-sync"} {
+		if exists(rel) {
+			t.Errorf("expected empty directory %s to be removed", rel)
+		}
+	}
+	if !exists("") {
+		t.Errorf("expected the output directory to be kept")
+	}
+}
+
+// TestManagerApplyFailureRecordsWrittenFiles verifies that the files written before a task
+// fails are recorded in the manifest, so that later runs can still prune them.
+func TestManagerApplyFailureRecordsWrittenFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "agent-sync.yml", `configVersion: "1.0"
+projects:
+  p:
+    outputDirs: [out]
+    tasks:
+      - type: command
+        inputs: [cmds/*.md]
+        outputs:
+          - agent: claude
+      - type: memory
+        inputs: [missing/*.md]
+        outputs:
+          - agent: claude
+user:
+  home: `+filepath.Join(dir, "home")+`
+  tasks: []
+`)
+	writeTestFile(t, dir, "cmds/a.md", "---\ndescription: a\n---\nA\n")
+
+	mgr, err := NewManager(filepath.Join(dir, "agent-sync.yml"), zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+	if err := mgr.Apply(false, false); err == nil {
+		t.Fatal("expected Apply to fail for a task without sources")
+	}
+	manifest, err := LoadManifest(&util.RealFileSystem{}, filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("LoadManifest returned error: %v", err)
+	}
+	if _, ok := manifest.Get(".claude/commands/a.md"); !ok {
+		t.Errorf("expected the written command to be recorded, got %+v", manifest.Files)
+	}
+}
@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// MCPProcessor processes mcp tasks.
+// Inputs are neutral YAML server lists which are combined into each agent's MCP configuration file.
+type MCPProcessor struct {
+	*BaseProcessor
+}
+
+// NewMCPProcessor creates a new MCPProcessor
+func NewMCPProcessor(base *BaseProcessor) *MCPProcessor {
+	return &MCPProcessor{BaseProcessor: base}
+}
+
+// mcpSource is a server list source file; it is parsed after templating
+type mcpSource struct {
+	path    string
+	content string
+}
+
+// mcpStrategy provides parsing, content access, and formatting for MCP server lists
+type mcpStrategy struct {
+	p         *BaseProcessor
+	agentName string
+}
+
+func (s mcpStrategy) Parse(absPath string, raw []byte) (mcpSource, error) {
+	return mcpSource{path: absPath, content: string(raw)}, nil
+}
+
+func (s mcpStrategy) GetContent(item mcpSource) string {
+	return item.content
+}
+
+func (s mcpStrategy) SetContent(item mcpSource, content string) mcpSource {
+	item.content = content
+	return item
+}
+
+func (s mcpStrategy) FormatOne(a agent.Agent, item mcpSource) (string, error) {
+	return s.FormatMany(a, []mcpSource{item})
+}
+
+func (s mcpStrategy) FormatMany(a agent.Agent, items []mcpSource) (string, error) {
+	c, ok := a.(agent.MCPConfigurator)
+	if !ok {
+		return "", fmt.Errorf("agent %s does not support MCP server configuration", a.ID())
+	}
+
+	var servers []model.MCPServer
+	seen := make(map[string]string)
+	for _, item := range items {
+		parsed, err := model.ParseMCPServers(item.path, []byte(item.content))
+		if err != nil {
+			return "", err
+		}
+		for _, server := range parsed {
+			if prev, ok := seen[server.Name]; ok {
+				return "", fmt.Errorf("MCP server %q is defined in both %s and %s", server.Name, prev, item.path)
+			}
+			seen[server.Name] = item.path
+			servers = append(servers, server)
+		}
+	}
+	return c.FormatMCPConfig(servers)
+}
+
+// Process implements the task processing for mcp task type
+func (p *MCPProcessor) Process(inputs []string, cfg *OutputConfig) (*TaskResult, error) {
+	configurator, ok := cfg.Agent.(agent.MCPConfigurator)
+	if !ok {
+		return nil, fmt.Errorf("agent %s does not support MCP server configuration", cfg.AgentName)
+	}
+	if cfg.RelPath == "" {
+		return nil, fmt.Errorf("agent %s has no default MCP configuration path in this scope; set outputPath", cfg.AgentName)
+	}
+	if cfg.IsDirectory {
+		return nil, fmt.Errorf("mcp output path must be a file: %s", cfg.RelPath)
+	}
+	strategy := mcpStrategy{p: p.BaseProcessor, agentName: cfg.AgentName}
+	result, err := processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configuration files shared with other settings (e.g. ~/.claude.json) are always merged
+	shared, ok := cfg.Agent.(agent.SharedMCPConfigurator)
+	if ok && shared.MCPConfigShared(p.userScope) && cfg.RelPath == configurator.MCPConfigPath(p.userScope) {
+		for i := range result.Files {
+			result.Files[i].merge = func(existing []byte, generated string, owned []string) (string, []string, error) {
+				return p.MergeContent(cfg, existing, generated, owned)
+			}
+		}
+	}
+	return result, nil
+}
+
+// MergeContent merges the generated MCP configuration into an existing file,
+// replacing servers with the same name and keeping everything else.
+// Servers generated by the previous run that are no longer defined are removed.
+func (p *MCPProcessor) MergeContent(cfg *OutputConfig, existing []byte, generated string, owned []string) (string, []string, error) {
+	return mergeJSON(2, false)(existing, generated, owned)
+}
+
+// GetOutputPath returns the appropriate output path for mcp tasks
+func (p *MCPProcessor) GetOutputPath(a agent.Agent, outputPath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+	if c, ok := a.(agent.MCPConfigurator); ok {
+		return c.MCPConfigPath(p.userScope)
+	}
+	return ""
+}
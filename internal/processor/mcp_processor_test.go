@@ -0,0 +1,153 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/util"
+	"go.uber.org/zap"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		abs := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", abs, err)
+		}
+	}
+}
+
+// TestMCPProcessor_Process verifies that server lists from all inputs are templated
+// and combined into a single agent configuration file.
+func TestMCPProcessor_Process(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"mcp/a.yml": "servers:\n  github:\n    command: npx\n{{ if isCursor }}    args: [cursor]\n{{ end }}",
+		"mcp/b.yml": "servers:\n  docs:\n    url: https://example.com/mcp\n",
+		"mcp/c.yml": "servers:\n  docs:\n    url: https://example.com/other\n",
+	})
+
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	mp := NewMCPProcessor(base)
+	cfg := &OutputConfig{Agent: &agent.Cursor{}, AgentName: "cursor", TaskType: "mcp"}
+	cfg.RelPath = mp.GetOutputPath(cfg.Agent, "")
+
+	result, err := mp.Process([]string{"mcp/a.yml", "mcp/b.yml"}, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].relPath != ".cursor/mcp.json" {
+		t.Fatalf("unexpected files: %+v", result.Files)
+	}
+	for _, want := range []string{`"docs"`, `"github"`, `"cursor"`} {
+		if !strings.Contains(result.Files[0].Content, want) {
+			t.Errorf("expected %s in output, got:\n%s", want, result.Files[0].Content)
+		}
+	}
+
+	_, err = mp.Process([]string{"mcp/b.yml", "mcp/c.yml"}, cfg)
+	if err == nil || !strings.Contains(err.Error(), `"docs" is defined in both`) {
+		t.Errorf("expected duplicate server error, got %v", err)
+	}
+}
+
+func TestMCPProcessor_Process_Errors(t *testing.T) {
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), t.TempDir(), agent.NewRegistry(), false)
+	mp := NewMCPProcessor(base)
+
+	tests := []struct {
+		name    string
+		cfg     *OutputConfig
+		wantErr string
+	}{
+		{"unsupported agent", &OutputConfig{Agent: &agent.Gemini{}, AgentName: "gemini", RelPath: "mcp.json"}, "does not support MCP"},
+		{"no default path", &OutputConfig{Agent: &agent.Cline{}, AgentName: "cline"}, "no default MCP configuration path"},
+		{"directory output", &OutputConfig{Agent: &agent.Claude{}, AgentName: "claude", RelPath: "mcp/", IsDirectory: true}, "must be a file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mp.Process([]string{"mcp.yml"}, tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Process() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMCPProcessor_ClaudeUserConfig verifies that ~/.claude.json is always merged, keeping
+// its other settings as written, and that servers removed from the sources are removed again.
+func TestMCPProcessor_ClaudeUserConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"agent-sync.yml": `configVersion: "1.0"
+user:
+  home: ` + filepath.Join(dir, "home") + `
+  tasks:
+    - type: mcp
+      inputs: [mcp/*.yml]
+      outputs:
+        - agent: claude
+`,
+		"mcp/servers.yml":   "servers:\n  github:\n    command: npx\n  docs:\n    url: https://example.com/mcp\n",
+		"home/.claude.json": `{"numStartups": 12, "tipsHistory": {"a": 1.50}, "mcpServers": {"manual": {"command": "m"}}}`,
+	})
+
+	apply := func() string {
+		t.Helper()
+		mgr, err := NewManager(filepath.Join(dir, "agent-sync.yml"), zap.NewNop(), nil)
+		if err != nil {
+			t.Fatalf("NewManager returned error: %v", err)
+		}
+		if err := mgr.Apply(false, false); err != nil {
+			t.Fatalf("Apply returned error: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "home", ".claude.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	got := apply()
+	want := `{
+  "numStartups": 12,
+  "tipsHistory": {
+    "a": 1.50
+  },
+  "mcpServers": {
+    "manual": {
+      "command": "m"
+    },
+    "docs": {
+      "type": "http",
+      "url": "https://example.com/mcp"
+    },
+    "github": {
+      "command": "npx",
+      "type": "stdio"
+    }
+  }
+}
+`
+	if got != want {
+		t.Errorf("merged config =\n%s\nwant\n%s", got, want)
+	}
+
+	writeTestFiles(t, dir, map[string]string{"mcp/servers.yml": "servers:\n  github:\n    command: npx\n"})
+	got = apply()
+	if strings.Contains(got, `"docs"`) {
+		t.Errorf("expected removed server to be pruned, got:\n%s", got)
+	}
+	for _, want := range []string{`"manual"`, `"github"`, `"numStartups": 12`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s to be kept, got:\n%s", want, got)
+		}
+	}
+}
@@ -2,9 +2,13 @@
 package processor
 
 import (
-	"strings"
+	"fmt"
+	"path/filepath"
 
 	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/frontmatter"
+	"github.com/uphy/agent-sync/internal/model"
+	"github.com/uphy/agent-sync/internal/util"
 )
 
 // MemoryProcessor processes memory tasks
@@ -17,37 +21,102 @@ func NewMemoryProcessor(base *BaseProcessor) *MemoryProcessor {
 	return &MemoryProcessor{BaseProcessor: base}
 }
 
-// memoryStrategy provides parsing, content access, and formatting for memory (string-based)
+// memoryStrategy provides parsing, content access, and formatting for memories
 type memoryStrategy struct {
 	p         *BaseProcessor
 	agentName string
 }
 
-func (s memoryStrategy) Parse(absPath string, raw []byte) (string, error) {
-	return string(raw), nil
+func (s memoryStrategy) Parse(absPath string, raw []byte) (model.Memory, error) {
+	m, err := model.ParseMemory(absPath, raw)
+	if err != nil {
+		return model.Memory{}, fmt.Errorf("parse memory from content %s: %w", absPath, err)
+	}
+	return *m, nil
 }
 
-func (s memoryStrategy) GetContent(item string) string {
-	return item
+func (s memoryStrategy) GetContent(item model.Memory) string {
+	return item.Content
 }
 
-func (s memoryStrategy) SetContent(item string, content string) string {
-	return content
+func (s memoryStrategy) SetContent(item model.Memory, content string) model.Memory {
+	item.Content = content
+	return item
 }
 
-func (s memoryStrategy) FormatOne(a agent.Agent, item string) (string, error) {
-	return a.FormatMemory(item)
+func (s memoryStrategy) FormatOne(a agent.Agent, item model.Memory) (string, error) {
+	if f, ok := a.(agent.MemoryFileFormatter); ok {
+		return f.FormatMemoryFile(item)
+	}
+	return a.FormatMemory([]model.Memory{item})
 }
 
-func (s memoryStrategy) FormatMany(a agent.Agent, items []string) (string, error) {
-	joined := strings.Join(items, "\n\n")
-	return a.FormatMemory(joined)
+func (s memoryStrategy) FormatMany(a agent.Agent, items []model.Memory) (string, error) {
+	return a.FormatMemory(items)
 }
 
 // Process implements the task processing for memory task type
 func (p *MemoryProcessor) Process(inputs []string, cfg *OutputConfig) (*TaskResult, error) {
+	if cfg.RelPath == "" {
+		return nil, fmt.Errorf("agent %s has no default memory path in this scope; set outputPath", cfg.AgentName)
+	}
 	strategy := memoryStrategy{p: p.BaseProcessor, agentName: cfg.AgentName}
-	return processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+
+	locator, ok := cfg.Agent.(agent.MemoryLocator)
+	if !ok {
+		return processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+	}
+
+	// Agents reading nested memory files get one output per subdirectory
+	dirs, groups, err := p.groupInputsByMemoryDir(locator, inputs)
+	if err != nil {
+		return nil, err
+	}
+	result := &TaskResult{Files: []ProcessedFile{}}
+	for _, dir := range dirs {
+		groupCfg := *cfg
+		if cfg.IsDirectory {
+			groupCfg.RelPath = filepath.Join(cfg.RelPath, dir) + "/"
+		} else {
+			groupCfg.RelPath = filepath.Join(dir, cfg.RelPath)
+		}
+		groupResult, err := processGeneric(p.BaseProcessor, groups[dir], &groupCfg, strategy)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, groupResult.Files...)
+	}
+	return result, nil
+}
+
+// groupInputsByMemoryDir groups inputs by the subdirectory the agent derives from their frontmatter.
+// The returned directories keep the order in which they first appear in inputs.
+func (p *MemoryProcessor) groupInputsByMemoryDir(locator agent.MemoryLocator, inputs []string) ([]string, map[string][]string, error) {
+	var dirs []string
+	groups := make(map[string][]string)
+	for _, input := range inputs {
+		absInputPath := input
+		if !filepath.IsAbs(input) {
+			absInputPath = util.JoinPath(p.absInputRoot, input)
+		}
+		fm, _, err := frontmatter.ParseFromFile(p.fs, absInputPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		dir, err := locator.MemoryDir(fm)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolve memory directory for %s: %w", input, err)
+		}
+		// Nested memory files are read from a project, never from the home directory
+		if dir != "" && p.userScope {
+			return nil, nil, fmt.Errorf("resolve memory directory for %s: a memory directory (%s) is only supported in project tasks", input, dir)
+		}
+		if _, ok := groups[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		groups[dir] = append(groups[dir], input)
+	}
+	return dirs, groups, nil
 }
 
 // GetOutputPath returns the appropriate output path for memory tasks
@@ -0,0 +1,151 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/util"
+	"go.uber.org/zap"
+)
+
+// TestMemoryProcessor_FileNamer verifies that agents implementing agent.FileNamer
+// control the per-file output names in directory mode.
+func TestMemoryProcessor_FileNamer(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "memories", "go-style.md")
+	if err := os.MkdirAll(filepath.Dir(in), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	src := "---\ncursor:\n  globs: \"**/*.go\"\n---\nUse gofmt.\n"
+	if err := os.WriteFile(in, []byte(src), 0o644); err != nil {
+		t.Fatalf("write %s: %v", in, err)
+	}
+
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	mp := NewMemoryProcessor(base)
+	cfg := &OutputConfig{
+		Agent:       &agent.Cursor{},
+		IsDirectory: true,
+		AgentName:   "cursor",
+		TaskType:    "memory",
+	}
+	cfg.RelPath = mp.GetOutputPath(cfg.Agent, "")
+
+	result, err := mp.Process([]string{in}, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(result.Files))
+	}
+	if got, want := result.Files[0].relPath, filepath.Join(".cursor", "rules", "go-style.mdc"); got != want {
+		t.Errorf("relPath = %q, want %q", got, want)
+	}
+	if !strings.Contains(result.Files[0].Content, "globs: \"**/*.go\"") {
+		t.Errorf("expected cursor globs in output, got:\n%s", result.Files[0].Content)
+	}
+}
+
+// TestMemoryProcessor_MemoryLocator verifies that agents implementing agent.MemoryLocator
+// receive one output per subdirectory requested by the sources' frontmatter.
+func TestMemoryProcessor_MemoryLocator(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"memories/root.md": "Root rules\n",
+		"memories/api.md":  "---\npath: packages/api\n---\nAPI rules\n",
+		"memories/web.md":  "---\npath: packages/web\n---\nWeb rules\n",
+	}
+	var inputs []string
+	for rel, content := range files {
+		abs := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", abs, err)
+		}
+		inputs = append(inputs, rel)
+	}
+	sort.Strings(inputs)
+
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	mp := NewMemoryProcessor(base)
+	cfg := &OutputConfig{
+		Agent:     &agent.Codex{},
+		AgentName: "codex",
+		TaskType:  "memory",
+	}
+	cfg.RelPath = mp.GetOutputPath(cfg.Agent, "")
+
+	result, err := mp.Process(inputs, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, f := range result.Files {
+		got[f.relPath] = f.Content
+	}
+	want := map[string]string{
+		"AGENTS.md": "Root rules",
+		filepath.Join("packages", "api", "AGENTS.md"): "API rules",
+		filepath.Join("packages", "web", "AGENTS.md"): "Web rules",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d files, got %v", len(want), got)
+	}
+	for path, body := range want {
+		if !strings.Contains(got[path], body) {
+			t.Errorf("%s: expected content containing %q, got %q", path, body, got[path])
+		}
+	}
+}
+
+// TestMemoryProcessor_NoDefaultPath verifies that agents without a memory path in the
+// task's scope fail instead of writing where the agent does not read
+func TestMemoryProcessor_NoDefaultPath(t *testing.T) {
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), t.TempDir(), agent.NewRegistry(), true)
+	mp := NewMemoryProcessor(base)
+	cfg := &OutputConfig{
+		Agent:     &agent.Cursor{},
+		AgentName: "cursor",
+		TaskType:  "memory",
+	}
+	cfg.RelPath = mp.GetOutputPath(cfg.Agent, "")
+
+	_, err := mp.Process([]string{"memory.md"}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "agent cursor has no default memory path in this scope") {
+		t.Fatalf("expected a missing path error, got %v", err)
+	}
+}
+
+// TestMemoryProcessor_MemoryLocatorUserScope verifies that memory directories are rejected
+// in user scope, where the agent only reads the memory file of the home directory
+func TestMemoryProcessor_MemoryLocatorUserScope(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "memories", "api.md")
+	if err := os.MkdirAll(filepath.Dir(in), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(in, []byte("---\npath: packages/api\n---\nAPI rules\n"), 0o644); err != nil {
+		t.Fatalf("write %s: %v", in, err)
+	}
+
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), true)
+	mp := NewMemoryProcessor(base)
+	cfg := &OutputConfig{
+		Agent:     &agent.Codex{},
+		AgentName: "codex",
+		TaskType:  "memory",
+	}
+	cfg.RelPath = mp.GetOutputPath(cfg.Agent, "")
+
+	_, err := mp.Process([]string{in}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "only supported in project tasks") {
+		t.Fatalf("expected a user scope error, got %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/frontmatter"
+)
+
+// mergeFunc combines generated content with the content of an existing output file.
+// existing is nil when the file does not exist yet. owned lists what the task generated
+// into the file the last time, as returned by the previous merge; owned content that is
+// no longer generated is removed. The merge returns what the task owns in the merged file.
+type mergeFunc func(existing []byte, generated string, owned []string) (merged string, nextOwned []string, err error)
+
+// mergeJSON merges a generated JSON object into an existing JSON document.
+// Objects are merged recursively down to depth levels; below that, and for any
+// non-object value, the generated value replaces the existing one. A negative
+// depth merges objects at every level. When unionArrays is true, arrays present
+// in both documents are combined instead, keeping existing items first and
+// skipping duplicates. Keys only present in the existing document are kept, and
+// the key order and numbers of the existing document are written back unchanged.
+//
+// Owned content is tracked as JSON pointers to the values the generated document
+// replaced and to the array items it added; items that were already present are
+// left to their owner. Owned values that are no longer generated are removed.
+func mergeJSON(depth int, unionArrays bool) mergeFunc {
+	return func(existing []byte, generated string, owned []string) (string, []string, error) {
+		overlay, err := parseJSONObject([]byte(generated))
+		if err != nil {
+			return "", nil, fmt.Errorf("parse generated JSON: %w", err)
+		}
+		pointers := make(map[string]bool)
+		collectJSONPointers(overlay, "", depth, unionArrays, pointers)
+		if len(bytes.TrimSpace(existing)) == 0 {
+			return generated, sortedKeys(pointers), nil
+		}
+		base, err := parseJSONObject(existing)
+		if err != nil {
+			return "", nil, fmt.Errorf("parse existing JSON: %w", err)
+		}
+
+		previous := make(map[string]bool, len(owned))
+		stale := make(map[string]bool)
+		for _, ptr := range owned {
+			previous[ptr] = true
+			if !pointers[ptr] {
+				stale[ptr] = true
+			}
+		}
+		if len(stale) > 0 {
+			pruneJSONObject(base, "", stale)
+		}
+
+		next := make(map[string]bool, len(pointers))
+		mergeJSONObject(base, overlay, "", depth, unionArrays, previous, next)
+		merged, err := renderJSON(base)
+		if err != nil {
+			return "", nil, fmt.Errorf("marshal merged JSON: %w", err)
+		}
+		return merged, sortedKeys(next), nil
+	}
+}
+
+// mergeJSONObject merges overlay into base; see mergeJSON for the semantics.
+// The pointers of the values owned after the merge are added to next.
+func mergeJSONObject(base, overlay *jsonObject, ptr string, depth int, unionArrays bool, previous, next map[string]bool) {
+	for _, key := range overlay.keys {
+		v := overlay.values[key]
+		p := ptr + "/" + jsonPointerToken(key)
+		baseValue, _ := base.get(key)
+		if overlayObj, ok := v.(*jsonObject); ok && depth != 1 {
+			baseObj, ok := baseValue.(*jsonObject)
+			if !ok {
+				baseObj = newJSONObject()
+				base.set(key, baseObj)
+			}
+			mergeJSONObject(baseObj, overlayObj, p, depth-1, unionArrays, previous, next)
+			continue
+		}
+		if overlayList, ok := v.([]any); ok && unionArrays {
+			baseList, _ := baseValue.([]any)
+			merged := append([]any{}, baseList...)
+			present := make(map[string]bool, len(merged))
+			for _, item := range merged {
+				present[canonicalJSON(item)] = true
+			}
+			for _, item := range overlayList {
+				c := canonicalJSON(item)
+				itemPtr := p + "/" + jsonPointerToken(c)
+				if present[c] {
+					// Items added by someone else stay theirs
+					if previous[itemPtr] {
+						next[itemPtr] = true
+					}
+					continue
+				}
+				present[c] = true
+				merged = append(merged, item)
+				next[itemPtr] = true
+			}
+			base.set(key, merged)
+			continue
+		}
+		base.set(key, v)
+		next[p] = true
+	}
+}
+
+// collectJSONPointers adds the pointers of the values mergeJSON takes from a generated object to out
+func collectJSONPointers(obj *jsonObject, ptr string, depth int, unionArrays bool, out map[string]bool) {
+	for _, key := range obj.keys {
+		p := ptr + "/" + jsonPointerToken(key)
+		switch v := obj.values[key].(type) {
+		case *jsonObject:
+			if depth != 1 {
+				collectJSONPointers(v, p, depth-1, unionArrays, out)
+				continue
+			}
+		case []any:
+			if unionArrays {
+				for _, item := range v {
+					out[p+"/"+jsonPointerToken(canonicalJSON(item))] = true
+				}
+				continue
+			}
+		}
+		out[p] = true
+	}
+}
+
+// pruneJSONObject removes the values at the stale pointers below obj.
+// Objects and arrays emptied by the removal are removed as well.
+func pruneJSONObject(obj *jsonObject, ptr string, stale map[string]bool) {
+	for _, key := range append([]string{}, obj.keys...) {
+		p := ptr + "/" + jsonPointerToken(key)
+		if stale[p] {
+			obj.delete(key)
+			continue
+		}
+		switch v := obj.values[key].(type) {
+		case *jsonObject:
+			if len(v.keys) == 0 {
+				continue
+			}
+			pruneJSONObject(v, p, stale)
+			if len(v.keys) == 0 {
+				obj.delete(key)
+			}
+		case []any:
+			if len(v) == 0 {
+				continue
+			}
+			kept := make([]any, 0, len(v))
+			for _, item := range v {
+				if !stale[p+"/"+jsonPointerToken(canonicalJSON(item))] {
+					kept = append(kept, item)
+				}
+			}
+			if len(kept) == 0 {
+				obj.delete(key)
+			} else {
+				obj.set(key, kept)
+			}
+		}
+	}
+}
+
+// mergeMaps returns base with overlay merged into it; see mergeJSON for the semantics
+func mergeMaps(base, overlay map[string]any, depth int, unionArrays bool) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		if baseMap, ok := merged[k].(map[string]any); ok && depth != 1 {
+			if overlayMap, ok := v.(map[string]any); ok {
+				merged[k] = mergeMaps(baseMap, overlayMap, depth-1, unionArrays)
+				continue
+			}
+		}
+		if baseList, ok := merged[k].([]any); ok && unionArrays {
+			if overlayList, ok := v.([]any); ok {
+				merged[k] = unionLists(baseList, overlayList)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// unionLists appends the items of overlay missing from base
+func unionLists(base, overlay []any) []any {
+	merged := append([]any{}, base...)
+	for _, item := range overlay {
+		if !containsValue(merged, item) {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeLines merges a generated line-based file into an existing one.
+// Existing lines are kept as they are and generated lines not yet present are appended.
+// Owned lines that are no longer generated are removed; lines that were already present
+// before agent-sync generated them are left to their owner.
+func mergeLines(existing []byte, generated string, owned []string) (string, []string, error) {
+	generatedLines := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimRight(generated, "\n"), "\n") {
+		generatedLines[line] = true
+	}
+	previous := make(map[string]bool, len(owned))
+	for _, line := range owned {
+		previous[line] = true
+	}
+
+	present := make(map[string]bool)
+	var lines []string
+	if text := strings.TrimRight(string(existing), "\n"); text != "" {
+		for _, line := range strings.Split(text, "\n") {
+			if previous[line] && !generatedLines[line] {
+				continue
+			}
+			present[line] = true
+			lines = append(lines, line)
+		}
+	}
+	var next []string
+	for _, line := range strings.Split(strings.TrimRight(generated, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		if present[line] {
+			if previous[line] {
+				next = append(next, line)
+				previous[line] = false
+			}
+			continue
+		}
+		present[line] = true
+		lines = append(lines, line)
+		next = append(next, line)
+	}
+	return strings.Join(lines, "\n") + "\n", next, nil
+}
+
+// mergeKeyedList merges a generated document holding a list of entries under listKey
+// into an existing document. Existing entries whose idKey matches a generated entry
+// are replaced in place, generated entries not yet present are appended, and all
+// other entries and top-level keys are kept. Owned entries, the ids generated by the
+// previous run, are removed when they are no longer generated. Documents are JSON when
+// asJSON is true and YAML otherwise; key order is preserved.
+func mergeKeyedList(listKey, idKey string, asJSON bool) mergeFunc {
+	return func(existing []byte, generated string, owned []string) (string, []string, error) {
+		if asJSON {
+			overlay, err := parseJSONObject([]byte(generated))
+			if err != nil {
+				return "", nil, fmt.Errorf("parse generated JSON: %w", err)
+			}
+			overlayValue, _ := overlay.get(listKey)
+			overlayList, _ := overlayValue.([]any)
+			ids := entryIDs(overlayList, idKey)
+			if len(bytes.TrimSpace(existing)) == 0 {
+				return generated, ids, nil
+			}
+			base, err := parseJSONObject(existing)
+			if err != nil {
+				return "", nil, fmt.Errorf("parse existing JSON: %w", err)
+			}
+			baseValue, _ := base.get(listKey)
+			baseList, _ := baseValue.([]any)
+			base.set(listKey, mergeKeyedEntries(baseList, overlayList, idKey, owned))
+
+			merged, err := renderJSON(base)
+			if err != nil {
+				return "", nil, fmt.Errorf("marshal merged JSON: %w", err)
+			}
+			return merged, ids, nil
+		}
+
+		var overlay yaml.MapSlice
+		if err := yaml.UnmarshalWithOptions([]byte(generated), &overlay, yaml.UseOrderedMap()); err != nil {
+			return "", nil, fmt.Errorf("parse generated YAML: %w", err)
+		}
+		overlayList, _ := mapSliceValue(overlay, listKey).([]any)
+		ids := entryIDs(overlayList, idKey)
+		if len(bytes.TrimSpace(existing)) == 0 {
+			return generated, ids, nil
+		}
+		var base yaml.MapSlice
+		if err := yaml.UnmarshalWithOptions(existing, &base, yaml.UseOrderedMap()); err != nil {
+			return "", nil, fmt.Errorf("parse existing YAML: %w", err)
+		}
+		baseList, _ := mapSliceValue(base, listKey).([]any)
+		merged := mergeKeyedEntries(baseList, overlayList, idKey, owned)
+
+		found := false
+		for i := range base {
+			if base[i].Key == listKey {
+				base[i].Value = merged
+				found = true
+			}
+		}
+		if !found {
+			base = append(base, yaml.MapItem{Key: listKey, Value: merged})
+		}
+		out, err := frontmatter.RenderYAML(base)
+		return out, ids, err
+	}
+}
+
+// mergeKeyedEntries replaces entries of base by the overlay entries with the same id,
+// removes the owned entries missing from overlay and appends the overlay entries missing from base
+func mergeKeyedEntries(base, overlay []any, idKey string, owned []string) []any {
+	overlayByID := make(map[string]any, len(overlay))
+	for _, entry := range overlay {
+		if id, ok := entryID(entry, idKey); ok {
+			overlayByID[id] = entry
+		}
+	}
+	stale := make(map[string]bool, len(owned))
+	for _, id := range owned {
+		if _, ok := overlayByID[id]; !ok {
+			stale[id] = true
+		}
+	}
+
+	merged := make([]any, 0, len(base)+len(overlay))
+	used := make(map[string]bool, len(overlay))
+	for _, entry := range base {
+		if id, ok := entryID(entry, idKey); ok {
+			if stale[id] {
+				continue
+			}
+			if replacement, generated := overlayByID[id]; generated {
+				merged = append(merged, replacement)
+				used[id] = true
+				continue
+			}
+		}
+		merged = append(merged, entry)
+	}
+	for _, entry := range overlay {
+		if id, ok := entryID(entry, idKey); ok && used[id] {
+			continue
+		}
+		merged = append(merged, entry)
+	}
+	return merged
+}
+
+// entryIDs returns the ids of the entries of a list that have one
+func entryIDs(entries []any, idKey string) []string {
+	var ids []string
+	for _, entry := range entries {
+		if id, ok := entryID(entry, idKey); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// entryID returns the string value of idKey in a list entry decoded from JSON or YAML
+func entryID(entry any, idKey string) (string, bool) {
+	var v any
+	switch e := entry.(type) {
+	case *jsonObject:
+		v, _ = e.get(idKey)
+	case yaml.MapSlice:
+		v = mapSliceValue(e, idKey)
+	default:
+		return "", false
+	}
+	id, ok := v.(string)
+	return id, ok && id != ""
+}
+
+// mapSliceValue returns the value of key in an ordered YAML mapping, or nil
+func mapSliceValue(m yaml.MapSlice, key string) any {
+	for _, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			return item.Value
+		}
+	}
+	return nil
+}
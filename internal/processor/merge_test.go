@@ -0,0 +1,178 @@
+package processor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeJSON(t *testing.T) {
+	existing := `{
+  "inputs": [],
+  "mcpServers": {
+    "github": {"command": "old", "env": {"A": "1"}},
+    "manual": {"command": "manual-server"}
+  }
+}`
+	generated := `{"mcpServers": {"github": {"command": "npx"}}}`
+
+	tests := []struct {
+		name  string
+		depth int
+		want  string
+	}{
+		{
+			name:  "replace servers below depth",
+			depth: 2,
+			want: `{
+  "inputs": [],
+  "mcpServers": {
+    "github": {
+      "command": "npx"
+    },
+    "manual": {
+      "command": "manual-server"
+    }
+  }
+}
+`,
+		},
+		{
+			name:  "deep merge",
+			depth: -1,
+			want: `{
+  "inputs": [],
+  "mcpServers": {
+    "github": {
+      "command": "npx",
+      "env": {
+        "A": "1"
+      }
+    },
+    "manual": {
+      "command": "manual-server"
+    }
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := mergeJSON(tt.depth, false)([]byte(existing), generated, nil)
+			if err != nil {
+				t.Fatalf("mergeJSON() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("mergeJSON() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeJSON_Errors(t *testing.T) {
+	if got, _, err := mergeJSON(2, false)([]byte("  \n"), "{}\n", nil); err != nil || got != "{}\n" {
+		t.Errorf("empty existing file: got %q, %v", got, err)
+	}
+	if _, _, err := mergeJSON(2, false)([]byte("not json"), "{}", nil); err == nil {
+		t.Error("expected error for invalid existing JSON")
+	}
+}
+
+func TestMergeJSON_Owned(t *testing.T) {
+	existing := `{"mcpServers": {"manual": {"command": "m"}, "old": {"command": "o"}, "github": {"command": "old"}}, "count": 1e3}`
+	generated := `{"mcpServers": {"github": {"command": "npx"}}}`
+
+	got, owned, err := mergeJSON(2, false)([]byte(existing), generated, []string{"/mcpServers/github", "/mcpServers/old"})
+	if err != nil {
+		t.Fatalf("mergeJSON() error = %v", err)
+	}
+	want := "{\n  \"mcpServers\": {\n    \"manual\": {\n      \"command\": \"m\"\n    },\n    \"github\": {\n      \"command\": \"npx\"\n    }\n  },\n  \"count\": 1e3\n}\n"
+	if got != want {
+		t.Errorf("mergeJSON() =\n%s\nwant\n%s", got, want)
+	}
+	if want := []string{"/mcpServers/github"}; !reflect.DeepEqual(owned, want) {
+		t.Errorf("owned = %v, want %v", owned, want)
+	}
+
+	// Without an existing file everything generated is owned
+	_, owned, err = mergeJSON(-1, true)(nil, `{"permissions": {"deny": ["Read(a/b)"]}, "model": "x"}`, nil)
+	if err != nil {
+		t.Fatalf("mergeJSON() error = %v", err)
+	}
+	if want := []string{"/model", `/permissions/deny/"Read(a~1b)"`}; !reflect.DeepEqual(owned, want) {
+		t.Errorf("owned = %v, want %v", owned, want)
+	}
+}
+
+func TestMergeJSON_OwnedArrayItems(t *testing.T) {
+	merge := mergeJSON(-1, true)
+	existing := `{"permissions": {"deny": ["Read(manual)", "Read(old)", "Read(kept)"]}}`
+	generated := `{"permissions": {"deny": ["Read(kept)", "Read(new)"]}}`
+
+	// Read(kept) was added by hand, so it is not claimed; Read(old) was generated before
+	got, owned, err := merge([]byte(existing), generated, []string{`/permissions/deny/"Read(old)"`})
+	if err != nil {
+		t.Fatalf("mergeJSON() error = %v", err)
+	}
+	want := "{\n  \"permissions\": {\n    \"deny\": [\n      \"Read(manual)\",\n      \"Read(kept)\",\n      \"Read(new)\"\n    ]\n  }\n}\n"
+	if got != want {
+		t.Errorf("mergeJSON() =\n%s\nwant\n%s", got, want)
+	}
+	if want := []string{`/permissions/deny/"Read(new)"`}; !reflect.DeepEqual(owned, want) {
+		t.Errorf("owned = %v, want %v", owned, want)
+	}
+
+	// Lists emptied by removing owned items are removed with their parents
+	got, _, err = merge([]byte(`{"permissions": {"deny": ["Read(new)"]}, "model": "x"}`), `{}`, owned)
+	if err != nil {
+		t.Fatalf("mergeJSON() error = %v", err)
+	}
+	if want := "{\n  \"model\": \"x\"\n}\n"; got != want {
+		t.Errorf("mergeJSON() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeKeyedList(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		existing := "customModes:\n  - slug: ui\n    name: UI mode\n    groups:\n      - read\n  - slug: owned\n    name: Old\nother: kept\n"
+		generated := "customModes:\n  - slug: owned\n    name: New\n  - slug: added\n    name: Added\n"
+		got, _, err := mergeKeyedList("customModes", "slug", false)([]byte(existing), generated, nil)
+		if err != nil {
+			t.Fatalf("mergeKeyedList() error = %v", err)
+		}
+		want := "customModes:\n  - slug: ui\n    name: UI mode\n    groups:\n      - read\n  - slug: owned\n    name: New\n  - slug: added\n    name: Added\nother: kept\n"
+		if got != want {
+			t.Errorf("mergeKeyedList() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		existing := `{"customModes": [{"slug": "ui"}, {"slug": "owned", "name": "Old"}]}`
+		generated := `{"customModes": [{"slug": "owned", "name": "New"}]}`
+		got, _, err := mergeKeyedList("customModes", "slug", true)([]byte(existing), generated, nil)
+		if err != nil {
+			t.Fatalf("mergeKeyedList() error = %v", err)
+		}
+		want := "{\n  \"customModes\": [\n    {\n      \"slug\": \"ui\"\n    },\n    {\n      \"slug\": \"owned\",\n      \"name\": \"New\"\n    }\n  ]\n}\n"
+		if got != want {
+			t.Errorf("mergeKeyedList() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("owned", func(t *testing.T) {
+		existing := "customModes:\n  - slug: ui\n  - slug: removed\n  - slug: kept\n    name: Old\n"
+		generated := "customModes:\n  - slug: kept\n    name: New\n"
+		got, owned, err := mergeKeyedList("customModes", "slug", false)([]byte(existing), generated, []string{"removed", "kept"})
+		if err != nil {
+			t.Fatalf("mergeKeyedList() error = %v", err)
+		}
+		want := "customModes:\n  - slug: ui\n  - slug: kept\n    name: New\n"
+		if got != want {
+			t.Errorf("mergeKeyedList() =\n%s\nwant\n%s", got, want)
+		}
+		if !reflect.DeepEqual(owned, []string{"kept"}) {
+			t.Errorf("owned = %v, want [kept]", owned)
+		}
+	})
+}
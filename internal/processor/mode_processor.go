@@ -51,7 +51,40 @@ func (s modeStrategy) FormatMany(a agent.Agent, items []model.Mode) (string, err
 // Process implements the task processing for mode task type
 func (p *ModeProcessor) Process(inputs []string, cfg *OutputConfig) (*TaskResult, error) {
 	strategy := modeStrategy{p: p.BaseProcessor, agentName: cfg.AgentName}
-	return processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+	result, err := processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// User-scope mode files (e.g. Roo's custom_modes.yaml) are shared with modes created
+	// in the agent's UI, so aggregated keyed outputs are always merged there.
+	if p.userScope && !cfg.IsDirectory {
+		if keyer, ok := cfg.Agent.(agent.MergeKeyer); ok {
+			if _, _, keyed := keyer.MergeKeys(cfg.TaskType); keyed {
+				for i := range result.Files {
+					result.Files[i].merge = func(existing []byte, generated string, owned []string) (string, []string, error) {
+						return p.MergeContent(cfg, existing, generated, owned)
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// MergeContent merges an aggregated mode file into an existing one, replacing only
+// the entries generated by agent-sync and removing those it no longer generates.
+// It requires an agent implementing agent.MergeKeyer.
+func (p *ModeProcessor) MergeContent(cfg *OutputConfig, existing []byte, generated string, owned []string) (string, []string, error) {
+	keyer, ok := cfg.Agent.(agent.MergeKeyer)
+	if !ok {
+		return "", nil, fmt.Errorf("merge is not supported for %s mode outputs", cfg.AgentName)
+	}
+	listKey, idKey, keyed := keyer.MergeKeys(cfg.TaskType)
+	if !keyed || cfg.IsDirectory {
+		return "", nil, fmt.Errorf("merge is not supported for %s mode outputs", cfg.AgentName)
+	}
+	return mergeKeyedList(listKey, idKey, isJSONPath(cfg.RelPath))(existing, generated, owned)
 }
 
 // GetOutputPath returns the appropriate output path for mode tasks
@@ -389,3 +389,50 @@ roo:
 		}
 	})
 }
+
+// TestModeProcessor_UserScopeMerge verifies that user-scope Roo modes are merged into
+// custom_modes.yaml, keeping modes agent-sync does not own.
+func TestModeProcessor_UserScopeMerge(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"modes/review.md": "---\nroo:\n  slug: review\n  name: Review\n  roleDefinition: Reviewer\n---\nReview carefully.\n",
+	})
+
+	for _, userScope := range []bool{true, false} {
+		base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), userScope)
+		mp := NewModeProcessor(base)
+		cfg := &OutputConfig{Agent: &agent.Roo{}, AgentName: "roo", TaskType: "mode"}
+		cfg.RelPath = mp.GetOutputPath(cfg.Agent, "")
+
+		result, err := mp.Process([]string{"modes/review.md"}, cfg)
+		if err != nil {
+			t.Fatalf("process error: %v", err)
+		}
+		if got := result.Files[0].merge != nil; got != userScope {
+			t.Fatalf("userScope=%v: merge set = %v", userScope, got)
+		}
+		if !userScope {
+			continue
+		}
+
+		existing := "customModes:\n  - slug: ui-mode\n    name: Created in UI\n  - slug: review\n    name: Old review\n"
+		merged, _, err := result.Files[0].merge([]byte(existing), result.Files[0].Content, nil)
+		if err != nil {
+			t.Fatalf("merge error: %v", err)
+		}
+		if !strings.Contains(merged, "slug: ui-mode") || strings.Contains(merged, "Old review") {
+			t.Errorf("unexpected merged modes:\n%s", merged)
+		}
+		if strings.Index(merged, "ui-mode") > strings.Index(merged, "slug: review") {
+			t.Errorf("expected existing entry order to be kept:\n%s", merged)
+		}
+	}
+}
+
+func TestModeProcessor_MergeContent_Unsupported(t *testing.T) {
+	mp := NewModeProcessor(nil)
+	_, _, err := mp.MergeContent(&OutputConfig{Agent: &agent.Claude{}, AgentName: "claude", TaskType: "mode", RelPath: ".claude/agents/", IsDirectory: true}, []byte("x"), "y", nil)
+	if err == nil || !strings.Contains(err.Error(), "merge is not supported") {
+		t.Errorf("expected unsupported merge error, got %v", err)
+	}
+}
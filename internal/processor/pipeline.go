@@ -4,11 +4,13 @@ package processor
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/uphy/agent-sync/internal/agent"
 	"github.com/uphy/agent-sync/internal/config"
 	"github.com/uphy/agent-sync/internal/log"
+	"github.com/uphy/agent-sync/internal/template"
 	"github.com/uphy/agent-sync/internal/util"
 	"go.uber.org/zap"
 )
@@ -39,6 +41,20 @@ type Pipeline struct {
 	// When true, existing files will be overwritten without prompting.
 	Force bool
 
+	// ShowDiff indicates whether a dry run prints a unified diff for every file that would change.
+	ShowDiff bool
+
+	// ProjectName is the name of the project the task belongs to; empty for user-level tasks.
+	ProjectName string
+
+	// Vars are the template variables of the root and project levels, set by the Manager.
+	// Task and output-level vars are merged on top of them.
+	Vars map[string]any
+
+	// Catalog holds the commands and modes of the whole configuration, set by the Manager
+	// so that templates can reference them with the command and mode functions.
+	Catalog *template.Catalog
+
 	// fs is the file system interface used for all file operations,
 	// such as reading source files and writing output files.
 	fs util.FileSystem
@@ -52,6 +68,18 @@ type Pipeline struct {
 
 	// output is used for user-facing output of pipeline status and results.
 	output log.OutputWriter
+
+	// manifests records the files written (or planned in a dry run) per absolute output directory.
+	manifests map[string]*Manifest
+
+	// written holds the files written by previous pipelines of the same run, set by the Manager.
+	written map[string]*Manifest
+
+	// previous caches the manifests of the output directories as recorded by the last run.
+	previous map[string]*Manifest
+
+	// changes collects the files a dry run would create or modify.
+	changes []FileChange
 }
 
 // NewPipeline creates a new Pipeline with context and registers built-in agents.
@@ -102,6 +130,14 @@ func (p *Pipeline) newTaskProcessor(taskType string) (TaskProcessor, error) {
 		return NewCommandProcessor(base), nil
 	case "mode":
 		return NewModeProcessor(base), nil
+	case "skill":
+		return NewSkillProcessor(base), nil
+	case "mcp":
+		return NewMCPProcessor(base), nil
+	case "ignore":
+		return NewIgnoreProcessor(base), nil
+	case "settings":
+		return NewSettingsProcessor(base), nil
 	default:
 		return nil, fmt.Errorf("unsupported task type %s", taskType)
 	}
@@ -113,18 +149,18 @@ func (p *Pipeline) Execute() error {
 	// Log start of task execution
 	p.logTaskStart()
 
-	// Resolve and validate inputs
-	inputs, err := p.resolveAndValidateInputs()
-	if err != nil {
-		return err // Error already logged in resolveAndValidateInputs
-	}
-
 	// Create the appropriate task processor based on task type
 	processor, err := p.newTaskProcessor(p.Task.Type)
 	if err != nil {
 		return err
 	}
 
+	// Resolve and validate inputs
+	inputs, err := p.resolveAndValidateInputs(processor)
+	if err != nil {
+		return err // Error already logged in resolveAndValidateInputs
+	}
+
 	// Map to store files by agent
 	filesByAgent := make(map[string][]ProcessedFile)
 
@@ -136,6 +172,31 @@ func (p *Pipeline) Execute() error {
 			return err
 		}
 
+		// Managed-block outputs only replace the marked region of existing files
+		managed := false
+		switch output.Mode {
+		case "", config.OutputModeOverwrite:
+		case config.OutputModeManagedBlock:
+			if output.Merge {
+				return fmt.Errorf("merge cannot be combined with mode %s (agent %s)", output.Mode, output.Agent)
+			}
+			managed = true
+		default:
+			return fmt.Errorf("unsupported output mode %s (agent %s)", output.Mode, output.Agent)
+		}
+
+		// Outputs with merge enabled are combined with existing files when written
+		var merge mergeFunc
+		if output.Merge {
+			merger, ok := processor.(MergingProcessor)
+			if !ok {
+				return fmt.Errorf("merge is not supported for %s tasks (agent %s)", p.Task.Type, output.Agent)
+			}
+			merge = func(existing []byte, generated string, owned []string) (string, []string, error) {
+				return merger.MergeContent(cfg, existing, generated, owned)
+			}
+		}
+
 		// Process the task using the appropriate processor
 		result, err := processor.Process(inputs, cfg)
 		if err != nil {
@@ -146,6 +207,20 @@ func (p *Pipeline) Execute() error {
 		for _, file := range result.Files {
 			// Add agent name to each file
 			file.AgentName = output.Agent
+			if managed {
+				if file.merge != nil || !isManagedBlockPath(file.relPath) {
+					return fmt.Errorf("mode %s is not supported for %s (agent %s): only Markdown outputs can hold a managed block",
+						output.Mode, file.relPath, output.Agent)
+				}
+				if hasFrontmatter(file.Content) {
+					return fmt.Errorf("mode %s is not supported for %s (agent %s): the generated file starts with frontmatter, which must stay at the top of the file",
+						output.Mode, file.relPath, output.Agent)
+				}
+				file.managed = true
+			}
+			if file.merge == nil {
+				file.merge = merge
+			}
 			filesByAgent[output.Agent] = append(filesByAgent[output.Agent], file)
 		}
 	}
@@ -163,9 +238,15 @@ func (p *Pipeline) Execute() error {
 
 // resolveAndValidateInputs expands Task.Inputs with support for glob patterns and exclusions,
 // then validates that at least one input file was found.
-func (p *Pipeline) resolveAndValidateInputs() ([]string, error) {
+// Processors implementing InputPatternMapper may rewrite the patterns first.
+func (p *Pipeline) resolveAndValidateInputs(processor TaskProcessor) ([]string, error) {
+	patterns := p.Task.Inputs
+	if mapper, ok := processor.(InputPatternMapper); ok {
+		patterns = mapper.InputPatterns(patterns)
+	}
+
 	// Expand globs and apply exclusions
-	paths, err := p.fs.GlobWithExcludes(p.Task.Inputs, p.AbsInputRoot)
+	paths, err := p.fs.GlobWithExcludes(patterns, p.AbsInputRoot)
 	if err != nil {
 		p.logError("Failed to resolve inputs", err,
 			zap.Strings("inputs", p.Task.Inputs))
@@ -217,14 +298,35 @@ func (p *Pipeline) getOutputConfig(output config.Output) (*OutputConfig, error)
 			zap.Bool("isDirectory", isDirectory))
 	}
 
+	scope := template.ScopeProject
+	if p.UserScope {
+		scope = template.ScopeUser
+	}
+
 	return &OutputConfig{
 		Agent:       agent,
 		RelPath:     relOutputPath,
 		IsDirectory: isDirectory,
 		AgentName:   output.Agent,
+		TaskType:    p.Task.Type,
+		TemplateData: template.Data{
+			Vars:    config.MergeVars(p.Vars, p.Task.Vars, output.Vars),
+			Project: template.ProjectData{Name: p.ProjectName},
+			Task:    template.TaskData{Name: p.Task.Name, Type: p.Task.Type},
+			Scope:   scope,
+			Agent:   output.Agent,
+		},
+		Catalog: p.Catalog,
 	}, nil
 }
 
+// print writes a message to the output writer, if any
+func (p *Pipeline) print(msg string) {
+	if p.output != nil {
+		p.output.Print(msg)
+	}
+}
+
 // logError logs an error to both the zap logger and output writer
 func (p *Pipeline) logError(msg string, err error, fields ...zap.Field) {
 	// Log to zap logger
@@ -238,25 +340,35 @@ func (p *Pipeline) logError(msg string, err error, fields ...zap.Field) {
 
 // writeOutputFilesByAgent writes the processed files to all output directories, grouped by agent
 func (p *Pipeline) writeOutputFilesByAgent(filesByAgent map[string][]ProcessedFile) error {
-	if p.DryRun && p.output != nil {
-		// Process and print files grouped by agent
-		for agentName, files := range filesByAgent {
+	if p.DryRun {
+		// Process and print files grouped by agent, in a stable order
+		agentNames := make([]string, 0, len(filesByAgent))
+		for agentName := range filesByAgent {
+			agentNames = append(agentNames, agentName)
+		}
+		sort.Strings(agentNames)
+		for _, agentName := range agentNames {
+			files := filesByAgent[agentName]
+
 			// Count for per-agent summary
 			createCount := 0
 			modifyCount := 0
 			unchangedCount := 0
 
 			// Print agent header
-			p.output.Print(fmt.Sprintf("\nAgent: %s", agentName))
+			p.print(fmt.Sprintf("\nAgent: %s", agentName))
 
-			// Track status messages for this agent
-			var statusMessages []string
+			// Track status messages, and diffs in diff mode, for this agent
+			type fileStatus struct {
+				msg  string
+				diff string
+			}
+			var statuses []fileStatus
 
 			// Process all files for this agent across all output directories
 			for _, absOutputDir := range p.AbsOutputDirs {
 				for _, file := range files {
 					absOutputFile := filepath.Join(absOutputDir, file.relPath)
-					contentLength := len(file.Content)
 
 					if isSub, err := util.IsSub(absOutputDir, absOutputFile); err != nil {
 						return fmt.Errorf("check if output path is subdirectory: %w", err)
@@ -264,15 +376,22 @@ func (p *Pipeline) writeOutputFilesByAgent(filesByAgent map[string][]ProcessedFi
 						return fmt.Errorf("output path %s is not a subdirectory of %s", absOutputFile, absOutputDir)
 					}
 
+					content, owned, err := p.outputContent(absOutputDir, absOutputFile, file)
+					if err != nil {
+						return err
+					}
+					contentLength := len(content)
+
 					fileExists := p.fs.FileExists(absOutputFile)
 					unchanged := false
+					var existingContent []byte
 
 					// Check if file content would change
 					if fileExists {
-						existingContent, err := p.fs.ReadFile(absOutputFile)
+						existingContent, err = p.fs.ReadFile(absOutputFile)
 						if err == nil {
 							// Compare content
-							if string(existingContent) == file.Content {
+							if string(existingContent) == content {
 								unchanged = true
 								unchangedCount++
 							} else {
@@ -289,30 +408,78 @@ func (p *Pipeline) writeOutputFilesByAgent(filesByAgent map[string][]ProcessedFi
 						createCount++
 					}
 
+					p.recordFile(absOutputDir, absOutputFile, file, content, owned)
+
 					statusMsg := p.formatDryRunFileStatus(absOutputFile, contentLength, unchanged)
+
+					// Files that would need confirmation are flagged
+					if existingContent != nil && !unchanged && file.merge == nil && !p.Force {
+						modified, err := p.modifiedOutsideAgentSync(absOutputDir, absOutputFile, file, existingContent)
+						if err != nil {
+							return err
+						}
+						if modified {
+							statusMsg += " - modified outside agent-sync"
+						}
+					}
+
+					// Managed-block outputs report what happens to the block itself
+					if file.managed && existingContent != nil {
+						change, err := managedBlockChange(existingContent, file.Content)
+						if err != nil {
+							return fmt.Errorf("managed block in %s: %w", absOutputFile, err)
+						}
+						statusMsg += " - managed block " + change
+					}
 					p.logger.Info("[DRY RUN] " + statusMsg)
 
 					// Collect status messages without the redundant [DRY RUN] prefix
-					statusMessages = append(statusMessages, fmt.Sprintf("  %s", statusMsg))
+					status := fileStatus{msg: fmt.Sprintf("  %s", statusMsg)}
+					if !unchanged {
+						kind := ChangeModify
+						if !fileExists {
+							kind = ChangeCreate
+						}
+						p.changes = append(p.changes, FileChange{Path: absOutputFile, Kind: kind})
+					}
+					if p.ShowDiff && !unchanged {
+						if fileExists {
+							status.diff = util.UnifiedDiff(absOutputFile+" (current)", absOutputFile+" (generated)", string(existingContent), content)
+						} else {
+							status.diff = util.UnifiedDiff("/dev/null", absOutputFile, "", content)
+						}
+					}
+					statuses = append(statuses, status)
 				}
 			}
 
 			// Print all collected file statuses for this agent
-			for _, msg := range statusMessages {
-				p.output.Print(msg)
+			for _, status := range statuses {
+				p.print(status.msg)
+				if status.diff != "" && p.output != nil {
+					p.output.PrintDiff(status.diff)
+				}
 			}
 
 			// Print per-agent summary
-			p.output.Print(fmt.Sprintf("\n  Summary: %d files would be created, %d files would be modified, %d files would remain unchanged",
+			p.print(fmt.Sprintf("\n  Summary: %d files would be created, %d files would be modified, %d files would remain unchanged",
 				createCount, modifyCount, unchangedCount))
 		}
 	} else {
-		// Non-dry-run mode: actually write the files
+		// Non-dry-run mode: resolve the content of all files and check for manual edits
+		// before writing anything, so that a declined overwrite leaves the task untouched
+		type pendingWrite struct {
+			absOutputDir  string
+			absOutputFile string
+			file          ProcessedFile
+			content       string
+			owned         []string
+		}
+		var pending []pendingWrite
 		for _, files := range filesByAgent {
 			for _, absOutputDir := range p.AbsOutputDirs {
 				for _, file := range files {
 					absOutputFile := filepath.Join(absOutputDir, file.relPath)
-					contentLength := len(file.Content)
 
 					if isSub, err := util.IsSub(absOutputDir, absOutputFile); err != nil {
 						return fmt.Errorf("check if output path is subdirectory: %w", err)
@@ -320,18 +487,253 @@ func (p *Pipeline) writeOutputFilesByAgent(filesByAgent map[string][]ProcessedFi
 						return fmt.Errorf("output path %s is not a subdirectory of %s", absOutputFile, absOutputDir)
 					}
 
-					if err := p.fs.WriteFile(absOutputFile, []byte(file.Content)); err != nil {
-						return fmt.Errorf("write file %s: %w", absOutputFile, err)
+					content, owned, err := p.outputContent(absOutputDir, absOutputFile, file)
+					if err != nil {
+						return err
+					}
+
+					overwrite, err := p.confirmOverwrite(absOutputDir, absOutputFile, file, content)
+					if err != nil {
+						return err
 					}
-					p.logger.Info("Wrote file", zap.String("path", absOutputFile), zap.Int("bytes", contentLength))
+					if !overwrite {
+						// Keep the previous manifest entry so the file is checked again on the next run
+						if entry, ok, err := p.lastWrittenEntry(absOutputDir, absOutputFile); err != nil {
+							return err
+						} else if ok {
+							p.addManifestEntry(absOutputDir, entry)
+						}
+						continue
+					}
+					pending = append(pending, pendingWrite{absOutputDir, absOutputFile, file, content, owned})
 				}
 			}
 		}
+
+		for _, w := range pending {
+			if err := p.writeFile(w.absOutputFile, w.file, w.content); err != nil {
+				return fmt.Errorf("write file %s: %w", w.absOutputFile, err)
+			}
+			p.logger.Info("Wrote file", zap.String("path", w.absOutputFile), zap.Int("bytes", len(w.content)))
+			p.recordFile(w.absOutputDir, w.absOutputFile, w.file, w.content, w.owned)
+		}
 	}
 
 	return nil
 }
 
+// writeFile writes content to absOutputFile, with the file's mode when it has one
+func (p *Pipeline) writeFile(absOutputFile string, file ProcessedFile, content string) error {
+	if file.mode != 0 {
+		return p.fs.WriteFileMode(absOutputFile, []byte(content), file.mode)
+	}
+	return p.fs.WriteFile(absOutputFile, []byte(content))
+}
+
+// outputContent returns the content to write to absOutputFile.
+// Files with a merge function are combined with the existing file, if any,
+// and managed-block files replace only the managed block of the existing file.
+// For merged files it also returns what the task owns in the merged content.
+func (p *Pipeline) outputContent(absOutputDir, absOutputFile string, file ProcessedFile) (string, []string, error) {
+	if file.managed {
+		var existing []byte
+		if p.fs.FileExists(absOutputFile) {
+			var err error
+			if existing, err = p.fs.ReadFile(absOutputFile); err != nil {
+				return "", nil, fmt.Errorf("read existing file %s: %w", absOutputFile, err)
+			}
+		}
+		content, err := applyManagedBlock(existing, file.Content)
+		if err != nil {
+			return "", nil, fmt.Errorf("managed block in %s: %w", absOutputFile, err)
+		}
+		return content, nil, nil
+	}
+	if file.merge == nil {
+		return file.Content, nil, nil
+	}
+	var existing []byte
+	if p.fs.FileExists(absOutputFile) {
+		var err error
+		if existing, err = p.fs.ReadFile(absOutputFile); err != nil {
+			return "", nil, fmt.Errorf("read existing file %s for merge: %w", absOutputFile, err)
+		}
+	}
+	owned, err := p.previouslyOwned(absOutputDir, absOutputFile)
+	if err != nil {
+		return "", nil, err
+	}
+	merged, owned, err := file.merge(existing, file.Content, owned)
+	if err != nil {
+		return "", nil, fmt.Errorf("merge into %s: %w", absOutputFile, err)
+	}
+	return merged, owned, nil
+}
+
+// previouslyOwned returns what this task owned in a merged output file after the last run,
+// as recorded in the manifest of the output directory
+func (p *Pipeline) previouslyOwned(absOutputDir, absOutputFile string) ([]string, error) {
+	m, err := p.previousManifest(absOutputDir)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := m.Get(p.manifestPath(absOutputDir, absOutputFile))
+	if !ok {
+		return nil, nil
+	}
+	return entry.Owned[p.Task.Name], nil
+}
+
+// recordFile adds a written file to the manifest of its output directory,
+// together with what the task owns in it when the file is merged
+func (p *Pipeline) recordFile(absOutputDir, absOutputFile string, file ProcessedFile, content string, ownedContent []string) {
+	// Managed-block files are only owned by agent-sync within the block
+	owned := content
+	if file.managed {
+		owned = renderManagedBlock(file.Content)
+	}
+	entry := ManifestEntry{
+		Path:   p.manifestPath(absOutputDir, absOutputFile),
+		Hash:   hashContent([]byte(owned)),
+		Task:   p.Task.Name,
+		Shared: file.merge != nil || file.managed,
+	}
+	if len(ownedContent) > 0 {
+		entry.Owned = map[string][]string{p.Task.Name: ownedContent}
+	}
+	p.addManifestEntry(absOutputDir, entry)
+}
+
+// addManifestEntry adds an entry to the manifest of this run for an output directory
+func (p *Pipeline) addManifestEntry(absOutputDir string, entry ManifestEntry) {
+	if p.manifests == nil {
+		p.manifests = make(map[string]*Manifest)
+	}
+	m, ok := p.manifests[absOutputDir]
+	if !ok {
+		m = &Manifest{Version: manifestVersion}
+		p.manifests[absOutputDir] = m
+	}
+	m.Add(entry)
+}
+
+// manifestPath returns the manifest path of an output file
+func (p *Pipeline) manifestPath(absOutputDir, absOutputFile string) string {
+	rel, err := filepath.Rel(absOutputDir, absOutputFile)
+	if err != nil {
+		// Output files are always inside their output directory, checked before writing
+		return filepath.ToSlash(absOutputFile)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// lastWrittenEntry returns the manifest entry of the content agent-sync last wrote to an output file,
+// either earlier in this run or in a previous run as recorded in the manifest of the output directory.
+func (p *Pipeline) lastWrittenEntry(absOutputDir, absOutputFile string) (ManifestEntry, bool, error) {
+	path := p.manifestPath(absOutputDir, absOutputFile)
+	for _, manifests := range []map[string]*Manifest{p.manifests, p.written} {
+		if m, ok := manifests[absOutputDir]; ok {
+			if entry, ok := m.Get(path); ok {
+				return entry, true, nil
+			}
+		}
+	}
+
+	m, err := p.previousManifest(absOutputDir)
+	if err != nil {
+		return ManifestEntry{}, false, err
+	}
+	entry, ok := m.Get(path)
+	return entry, ok, nil
+}
+
+// previousManifest returns the manifest of an output directory as recorded by the last run
+func (p *Pipeline) previousManifest(absOutputDir string) (*Manifest, error) {
+	if p.previous == nil {
+		p.previous = make(map[string]*Manifest)
+	}
+	m, ok := p.previous[absOutputDir]
+	if !ok {
+		var err error
+		if m, err = LoadManifest(p.fs, absOutputDir); err != nil {
+			return nil, err
+		}
+		p.previous[absOutputDir] = m
+	}
+	return m, nil
+}
+
+// modifiedOutsideAgentSync reports whether an existing output file differs from what
+// agent-sync last wrote to it, or was never written by agent-sync.
+// For managed-block files only the managed block is compared.
+func (p *Pipeline) modifiedOutsideAgentSync(absOutputDir, absOutputFile string, file ProcessedFile, existing []byte) (bool, error) {
+	owned := existing
+	if file.managed {
+		start, end, found, err := findManagedBlock(string(existing))
+		if err != nil {
+			return false, fmt.Errorf("managed block in %s: %w", absOutputFile, err)
+		}
+		if !found {
+			// The block will be appended; the existing content is kept as written
+			return false, nil
+		}
+		owned = existing[start:end]
+	}
+
+	entry, ok, err := p.lastWrittenEntry(absOutputDir, absOutputFile)
+	if err != nil {
+		return false, err
+	}
+	return !ok || hashContent(owned) != entry.Hash, nil
+}
+
+// confirmOverwrite checks whether an existing output file may be overwritten.
+// Files modified outside agent-sync are shown as a diff and the user is asked for confirmation;
+// without a terminal an error is returned. Force skips the check, as do merged files,
+// which keep the existing content by design.
+func (p *Pipeline) confirmOverwrite(absOutputDir, absOutputFile string, file ProcessedFile, content string) (bool, error) {
+	if p.Force || file.merge != nil || !p.fs.FileExists(absOutputFile) {
+		return true, nil
+	}
+	existing, err := p.fs.ReadFile(absOutputFile)
+	if err != nil {
+		return false, fmt.Errorf("read existing file %s: %w", absOutputFile, err)
+	}
+	if string(existing) == content {
+		return true, nil
+	}
+	modified, err := p.modifiedOutsideAgentSync(absOutputDir, absOutputFile, file, existing)
+	if err != nil || !modified {
+		return !modified, err
+	}
+
+	diff := util.UnifiedDiff(absOutputFile+" (current)", absOutputFile+" (generated)", string(existing), content)
+	if p.output == nil || !p.output.IsInteractive() {
+		if p.output != nil {
+			p.output.PrintDiff(diff)
+		}
+		return false, fmt.Errorf("%s was modified outside agent-sync; use --force to overwrite it", absOutputFile)
+	}
+
+	p.output.PrintDiff(diff)
+	if p.output.Confirm(fmt.Sprintf("%s was modified outside agent-sync. Overwrite it?", absOutputFile)) {
+		return true, nil
+	}
+	p.logger.Info("Skipped file modified outside agent-sync", zap.String("path", absOutputFile))
+	p.output.Print(fmt.Sprintf("  [SKIP] %s", absOutputFile))
+	return false, nil
+}
+
+// Changes returns the files a dry run found would be created or modified
+func (p *Pipeline) Changes() []FileChange {
+	return p.changes
+}
+
+// Manifests returns the files written by Execute, or planned in a dry run, per absolute output directory
+func (p *Pipeline) Manifests() map[string]*Manifest {
+	return p.manifests
+}
+
 // writeOutputFiles writes the processed files to all output directories
 // Kept for backward compatibility
 func (p *Pipeline) writeOutputFiles(files []ProcessedFile) error {
@@ -2,11 +2,13 @@ package processor
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/uphy/agent-sync/internal/config"
+	"github.com/uphy/agent-sync/internal/log"
 	"github.com/uphy/agent-sync/internal/util"
 	"go.uber.org/zap"
 )
@@ -58,6 +60,30 @@ func (m *mockFileSystem) WriteFile(path string, data []byte) error {
 	return nil
 }
 
+func (m *mockFileSystem) WriteFileMode(path string, data []byte, perm os.FileMode) error {
+	return m.WriteFile(path, data)
+}
+
+func (m *mockFileSystem) FileMode(path string) (os.FileMode, error) {
+	if !m.FileExists(path) {
+		return 0, &util.ErrFileNotFound{Path: path}
+	}
+	return 0644, nil
+}
+
+func (m *mockFileSystem) RemoveFile(path string) error {
+	if !m.FileExists(path) {
+		return &util.ErrFileNotFound{Path: path}
+	}
+	delete(m.existingFiles, path)
+	delete(m.fileContents, path)
+	return nil
+}
+
+func (m *mockFileSystem) RemoveEmptyDir(path string) (bool, error) {
+	return false, nil
+}
+
 func (m *mockFileSystem) FileExists(path string) bool {
 	return m.existingFiles[path]
 }
@@ -118,10 +144,18 @@ func (m *mockOutputWriter) PrintVerbose(msg string) {
 	m.messages = append(m.messages, fmt.Sprintf("VERBOSE: %s", msg))
 }
 
+func (m *mockOutputWriter) PrintDiff(diff string) {
+	m.messages = append(m.messages, fmt.Sprintf("DIFF: %s", diff))
+}
+
 func (m *mockOutputWriter) Confirm(prompt string) bool {
 	return true
 }
 
+func (m *mockOutputWriter) IsInteractive() bool {
+	return true
+}
+
 // TestPipelineDryRun tests the dry run functionality
 func TestPipelineDryRun(t *testing.T) {
 	// Test cases
@@ -382,3 +416,115 @@ func TestFormatDryRunFileStatus(t *testing.T) {
 		})
 	}
 }
+
+// TestPipelineWriteMerge verifies that files with a merge function are combined
+// with existing files, both when writing and when reporting a dry run.
+func TestPipelineWriteMerge(t *testing.T) {
+	mockFS := newMockFileSystem([]string{"/output/.mcp.json"})
+	mockFS.SetFileContent("/output/.mcp.json", `{"mcpServers": {"manual": {"command": "m"}}}`)
+
+	pipeline := &Pipeline{
+		Task:          config.Task{Name: "test-task", Type: "mcp"},
+		AbsInputRoot:  "/input",
+		AbsOutputDirs: []string{"/output"},
+		fs:            mockFS,
+		logger:        zap.NewNop(),
+		output:        newMockOutputWriter(),
+	}
+	files := []ProcessedFile{
+		{relPath: ".mcp.json", Content: `{"mcpServers": {"github": {"command": "npx"}}}`, merge: mergeJSON(2, false)},
+		{relPath: "plain.json", Content: `{"a": 1}`, merge: mergeJSON(2, false)},
+	}
+	if err := pipeline.writeOutputFiles(files); err != nil {
+		t.Fatalf("writeOutputFiles returned error: %v", err)
+	}
+
+	want := "{\n  \"mcpServers\": {\n    \"manual\": {\n      \"command\": \"m\"\n    },\n    \"github\": {\n      \"command\": \"npx\"\n    }\n  }\n}\n"
+	if got := string(mockFS.writtenFiles["/output/.mcp.json"]); got != want {
+		t.Errorf("merged content =\n%s\nwant\n%s", got, want)
+	}
+	// Without an existing file the generated content is written unchanged
+	if got := string(mockFS.writtenFiles["/output/plain.json"]); got != `{"a": 1}` {
+		t.Errorf("content without existing file = %q", got)
+	}
+
+	// A dry run compares the merged content with the existing file
+	mockFS.SetFileContent("/output/.mcp.json", want)
+	output := newMockOutputWriter()
+	pipeline.DryRun = true
+	pipeline.output = output
+	if err := pipeline.writeOutputFiles(files[:1]); err != nil {
+		t.Fatalf("writeOutputFiles returned error: %v", err)
+	}
+	found := false
+	for _, msg := range output.messages {
+		if strings.Contains(msg, "[UNCHANGED]") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected merged file to be reported unchanged, got %v", output.messages)
+	}
+}
+
+// TestPipelineOverwriteProtection verifies that files modified outside agent-sync are only
+// overwritten after confirmation, fail without a terminal, and are overwritten with Force.
+func TestPipelineOverwriteProtection(t *testing.T) {
+	generated := "generated\n"
+	lastWritten := "written by agent-sync\n"
+
+	tests := []struct {
+		name        string
+		existing    string
+		interactive bool
+		confirm     bool
+		force       bool
+		wantErr     bool
+		wantWrite   bool
+		wantPrompt  bool
+	}{
+		{name: "unmodified file", existing: lastWritten, wantWrite: true},
+		{name: "modified file confirmed", existing: "edited\n", interactive: true, confirm: true, wantWrite: true, wantPrompt: true},
+		{name: "modified file declined", existing: "edited\n", interactive: true, wantPrompt: true},
+		{name: "modified file without terminal", existing: "edited\n", wantErr: true},
+		{name: "modified file with force", existing: "edited\n", force: true, wantWrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystem([]string{"/output/CLAUDE.md", "/output/" + ManifestPath})
+			mockFS.SetFileContent("/output/CLAUDE.md", tt.existing)
+			manifest := fmt.Sprintf(`{"version": 1, "files": [{"path": "CLAUDE.md", "hash": %q, "task": "test-task"}]}`,
+				hashContent([]byte(lastWritten)))
+			mockFS.SetFileContent("/output/"+ManifestPath, manifest)
+
+			output := log.NewTestOutput(false)
+			output.Interactive = tt.interactive
+			output.SetConfirmReturn(tt.confirm)
+			pipeline := &Pipeline{
+				Task:          config.Task{Name: "test-task", Type: "memory"},
+				AbsInputRoot:  "/input",
+				AbsOutputDirs: []string{"/output"},
+				Force:         tt.force,
+				fs:            mockFS,
+				logger:        zap.NewNop(),
+				output:        output,
+			}
+
+			err := pipeline.writeOutputFiles([]ProcessedFile{{relPath: "CLAUDE.md", Content: generated}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeOutputFiles error = %v, wantErr %v", err, tt.wantErr)
+			}
+			_, written := mockFS.writtenFiles["/output/CLAUDE.md"]
+			if written != tt.wantWrite {
+				t.Errorf("file written = %v, want %v", written, tt.wantWrite)
+			}
+			if prompted := len(output.ConfirmPrompts) > 0; prompted != tt.wantPrompt {
+				t.Errorf("prompted = %v, want %v", prompted, tt.wantPrompt)
+			}
+			if tt.wantPrompt && (len(output.Diffs) != 1 || !strings.Contains(output.Diffs[0], "-edited")) {
+				t.Errorf("expected a diff of the manual edit, got %v", output.Diffs)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@
 package processor
 
 import (
+	"os"
+
 	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/template"
 )
 
 // OutputConfig encapsulates output configuration settings
@@ -14,6 +17,12 @@ type OutputConfig struct {
 	RelPath     string
 	IsDirectory bool
 	AgentName   string // Original agent name from config
+	// TaskType is the type of the task being processed (e.g., "memory", "command", "mode", "skill", "mcp", "ignore", "settings")
+	TaskType string
+	// TemplateData is the data passed to the templates of input files; OutputPath is set per output file
+	TemplateData template.Data
+	// Catalog holds the commands and modes of the configuration that templates can reference
+	Catalog *template.Catalog
 }
 
 // ProcessedFile represents a processed output file
@@ -24,9 +33,34 @@ type ProcessedFile struct {
 	Content string
 	// AgentName is the name of the agent for this file
 	AgentName string
+	// merge, when set, combines Content with an existing file at the output path
+	merge mergeFunc
+	// managed, when set, writes Content into the agent-sync managed block of the output file
+	managed bool
+	// mode, when set, is the permission bits of the output file, e.g. of executable skill scripts
+	mode os.FileMode
 }
 
 // TaskResult represents the result of processing a task
 type TaskResult struct {
 	Files []ProcessedFile
 }
+
+// ChangeKind describes how applying a configuration would change an output file
+type ChangeKind string
+
+const (
+	// ChangeCreate means the file does not exist yet and would be created
+	ChangeCreate ChangeKind = "create"
+	// ChangeModify means the file exists but its content differs from the generated content
+	ChangeModify ChangeKind = "modify"
+	// ChangeDelete means the file was generated by a previous run but is no longer generated
+	ChangeDelete ChangeKind = "delete"
+)
+
+// FileChange is a change to an output file found by a dry run
+type FileChange struct {
+	// Path is the absolute path of the output file
+	Path string
+	Kind ChangeKind
+}
@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/agent"
+)
+
+// SettingsProcessor processes settings tasks.
+// Inputs are YAML settings documents that are deep-merged in order, so later inputs
+// (e.g. per-project overrides) take precedence over earlier ones.
+type SettingsProcessor struct {
+	*BaseProcessor
+}
+
+// NewSettingsProcessor creates a new SettingsProcessor
+func NewSettingsProcessor(base *BaseProcessor) *SettingsProcessor {
+	return &SettingsProcessor{BaseProcessor: base}
+}
+
+// settingsSource is a settings source file; it is parsed after templating
+type settingsSource struct {
+	path    string
+	content string
+}
+
+// settingsStrategy provides parsing, content access, and formatting for settings documents
+type settingsStrategy struct {
+	p         *BaseProcessor
+	agentName string
+}
+
+func (s settingsStrategy) Parse(absPath string, raw []byte) (settingsSource, error) {
+	return settingsSource{path: absPath, content: string(raw)}, nil
+}
+
+func (s settingsStrategy) GetContent(item settingsSource) string {
+	return item.content
+}
+
+func (s settingsStrategy) SetContent(item settingsSource, content string) settingsSource {
+	item.content = content
+	return item
+}
+
+func (s settingsStrategy) FormatOne(a agent.Agent, item settingsSource) (string, error) {
+	return s.FormatMany(a, []settingsSource{item})
+}
+
+func (s settingsStrategy) FormatMany(a agent.Agent, items []settingsSource) (string, error) {
+	f, ok := a.(agent.SettingsFormatter)
+	if !ok {
+		return "", fmt.Errorf("agent %s does not support settings", a.ID())
+	}
+
+	settings := map[string]any{}
+	for _, item := range items {
+		var doc map[string]any
+		if err := yaml.Unmarshal([]byte(item.content), &doc); err != nil {
+			return "", fmt.Errorf("failed to parse settings in %s: %w", item.path, err)
+		}
+		settings = mergeMaps(settings, doc, -1, false)
+	}
+	return f.FormatSettings(settings)
+}
+
+// Process implements the task processing for settings task type
+func (p *SettingsProcessor) Process(inputs []string, cfg *OutputConfig) (*TaskResult, error) {
+	if _, ok := cfg.Agent.(agent.SettingsFormatter); !ok {
+		return nil, fmt.Errorf("agent %s does not support settings", cfg.AgentName)
+	}
+	if cfg.IsDirectory {
+		return nil, fmt.Errorf("settings output path must be a file: %s", cfg.RelPath)
+	}
+
+	strategy := settingsStrategy{p: p.BaseProcessor, agentName: cfg.AgentName}
+	result, err := processGeneric(p.BaseProcessor, inputs, cfg, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Settings files hold keys agent-sync does not manage, so they are always merged
+	for i := range result.Files {
+		result.Files[i].merge = func(existing []byte, generated string, owned []string) (string, []string, error) {
+			return p.MergeContent(cfg, existing, generated, owned)
+		}
+	}
+	return result, nil
+}
+
+// MergeContent deep-merges generated settings into an existing settings file.
+// Values set by agent-sync replace existing ones and list items are added to existing
+// lists, so that lists shared with ignore tasks (permissions.deny) do not depend on the
+// task order. Values and list items generated by the previous run that are no longer
+// generated are removed; other keys and items are kept.
+func (p *SettingsProcessor) MergeContent(cfg *OutputConfig, existing []byte, generated string, owned []string) (string, []string, error) {
+	return mergeJSON(-1, true)(existing, generated, owned)
+}
+
+// GetOutputPath returns the appropriate output path for settings tasks
+func (p *SettingsProcessor) GetOutputPath(a agent.Agent, outputPath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+	if f, ok := a.(agent.SettingsFormatter); ok {
+		return f.SettingsPath(p.userScope)
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/util"
+	"go.uber.org/zap"
+)
+
+// TestSettingsProcessor_Process verifies that settings inputs are deep-merged in order
+// and that the output is merged into an existing settings file.
+func TestSettingsProcessor_Process(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"settings/base.yml": `model: sonnet
+env:
+  LOG_LEVEL: info
+permissions:
+  allow:
+    - "Bash(npm run test:*)"
+hooks:
+  PostToolUse:
+    - matcher: "Edit|Write"
+      hooks:
+        - type: command
+          command: "npx prettier --write"
+`,
+		"settings/project.yml": `env:
+  PROJECT: {{ if isClaude }}demo{{ end }}
+permissions:
+  allow:
+    - "Bash(make:*)"
+`,
+	})
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	sp := NewSettingsProcessor(base)
+	cfg := &OutputConfig{Agent: &agent.Claude{}, AgentName: "claude", TaskType: "settings"}
+	cfg.RelPath = sp.GetOutputPath(cfg.Agent, "")
+
+	result, err := sp.Process([]string{"settings/base.yml", "settings/project.yml"}, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].relPath != ".claude/settings.json" {
+		t.Fatalf("unexpected files: %+v", result.Files)
+	}
+	file := result.Files[0]
+	want := `{
+  "env": {
+    "LOG_LEVEL": "info",
+    "PROJECT": "demo"
+  },
+  "hooks": {
+    "PostToolUse": [
+      {
+        "hooks": [
+          {
+            "command": "npx prettier --write",
+            "type": "command"
+          }
+        ],
+        "matcher": "Edit|Write"
+      }
+    ]
+  },
+  "model": "sonnet",
+  "permissions": {
+    "allow": [
+      "Bash(make:*)"
+    ]
+  }
+}
+`
+	if file.Content != want {
+		t.Errorf("content =\n%s\nwant\n%s", file.Content, want)
+	}
+
+	if file.merge == nil {
+		t.Fatal("expected settings output to be merged")
+	}
+	existing := `{"permissions": {"allow": ["Read", "Edit"], "defaultMode": "plan"}, "statusLine": {"type": "command"}, "theme": "dark"}`
+	// Edit and theme were generated by the previous run and are no longer in the sources
+	merged, owned, err := file.merge([]byte(existing), file.Content, []string{`/permissions/allow/"Edit"`, "/theme"})
+	if err != nil {
+		t.Fatalf("merge error: %v", err)
+	}
+	for _, want := range []string{`"defaultMode": "plan"`, `"statusLine"`, `"Read"`, `"Bash(make:*)"`} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("expected %s in merged settings, got:\n%s", want, merged)
+		}
+	}
+	for _, stale := range []string{`"Edit"`, `"theme"`} {
+		if strings.Contains(merged, stale) {
+			t.Errorf("expected previously generated %s to be removed, got:\n%s", stale, merged)
+		}
+	}
+	if !strings.Contains(strings.Join(owned, " "), "/model") {
+		t.Errorf("expected generated keys to be owned, got %v", owned)
+	}
+}
+
+// TestSettingsAndIgnoreOrder verifies that settings and ignore tasks writing the same
+// settings file produce the same deny rules in either order, and keep them on later runs.
+func TestSettingsAndIgnoreOrder(t *testing.T) {
+	settings := (&SettingsProcessor{}).MergeContent
+	ignore := (&IgnoreProcessor{}).MergeContent
+	cfg := &OutputConfig{RelPath: ".claude/settings.json"}
+	settingsJSON := `{"permissions": {"deny": ["Bash(rm:*)"]}}`
+	ignoreJSON := `{"permissions": {"deny": ["Read(.env)"]}}`
+
+	type step func(existing []byte, owned []string) (string, []string, error)
+	steps := map[string]step{
+		"settings": func(existing []byte, owned []string) (string, []string, error) {
+			return settings(cfg, existing, settingsJSON, owned)
+		},
+		"ignore": func(existing []byte, owned []string) (string, []string, error) {
+			return ignore(cfg, existing, ignoreJSON, owned)
+		},
+	}
+	for _, order := range [][]string{{"settings", "ignore"}, {"ignore", "settings"}} {
+		content := `{"permissions": {"deny": ["Read(manual)"]}}`
+		owned := map[string][]string{}
+		// Run twice: the second run sees what each task owned after the first one
+		for run := 0; run < 2; run++ {
+			for _, task := range order {
+				merged, next, err := steps[task]([]byte(content), owned[task])
+				if err != nil {
+					t.Fatalf("%v: merge error: %v", order, err)
+				}
+				content, owned[task] = merged, next
+			}
+		}
+		for _, rule := range []string{"Read(manual)", "Bash(rm:*)", "Read(.env)"} {
+			if strings.Count(content, rule) != 1 {
+				t.Errorf("%v: expected %s once, got:\n%s", order, rule, content)
+			}
+		}
+	}
+}
+
+func TestSettingsProcessor_Process_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"bad.yml": "permissions:\n  allow: Bash\n",
+	})
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	sp := NewSettingsProcessor(base)
+
+	tests := []struct {
+		name    string
+		cfg     *OutputConfig
+		wantErr string
+	}{
+		{"unsupported agent", &OutputConfig{Agent: &agent.Roo{}, AgentName: "roo", RelPath: "settings.json"}, "does not support settings"},
+		{"invalid permissions", &OutputConfig{Agent: &agent.Claude{}, AgentName: "claude", RelPath: ".claude/settings.json"}, "permissions.allow must be a list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sp.Process([]string{"bad.yml"}, tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Process() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
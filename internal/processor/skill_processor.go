@@ -0,0 +1,161 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/model"
+	"github.com/uphy/agent-sync/internal/util"
+)
+
+// SkillProcessor processes skill tasks.
+// Each input is a skill directory; its SKILL.md is parsed and formatted for the agent,
+// and the remaining files are copied alongside it.
+type SkillProcessor struct {
+	*BaseProcessor
+}
+
+// NewSkillProcessor creates a new SkillProcessor
+func NewSkillProcessor(base *BaseProcessor) *SkillProcessor {
+	return &SkillProcessor{BaseProcessor: base}
+}
+
+// skillStrategy provides parsing, content access, and formatting for skill entry files
+type skillStrategy struct {
+	p         *BaseProcessor
+	agentName string
+}
+
+func (s skillStrategy) Parse(absPath string, raw []byte) (model.Skill, error) {
+	sk, err := model.ParseSkill(absPath, raw)
+	if err != nil {
+		return model.Skill{}, fmt.Errorf("parse skill from content %s: %w", absPath, err)
+	}
+	return *sk, nil
+}
+
+func (s skillStrategy) GetContent(item model.Skill) string {
+	return item.Content
+}
+
+func (s skillStrategy) SetContent(item model.Skill, content string) model.Skill {
+	item.Content = content
+	return item
+}
+
+func (s skillStrategy) FormatOne(a agent.Agent, item model.Skill) (string, error) {
+	f, ok := a.(agent.SkillFormatter)
+	if !ok {
+		return "", fmt.Errorf("agent %s does not support skills", a.ID())
+	}
+	return f.FormatSkill(item)
+}
+
+func (s skillStrategy) FormatMany(a agent.Agent, items []model.Skill) (string, error) {
+	return "", fmt.Errorf("skills cannot be concatenated into a single file")
+}
+
+// InputPatterns maps skill directory patterns to the SKILL.md files inside them,
+// so that inputs like "skills/*" resolve to one entry file per skill directory.
+func (p *SkillProcessor) InputPatterns(patterns []string) []string {
+	mapped := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if filepath.Base(pattern) == model.SkillFileName {
+			mapped = append(mapped, pattern)
+			continue
+		}
+		mapped = append(mapped, strings.TrimSuffix(pattern, "/")+"/"+model.SkillFileName)
+	}
+	return mapped
+}
+
+// Process implements the task processing for skill task type
+func (p *SkillProcessor) Process(inputs []string, cfg *OutputConfig) (*TaskResult, error) {
+	if _, ok := cfg.Agent.(agent.SkillFormatter); !ok {
+		return nil, fmt.Errorf("agent %s does not support skills", cfg.AgentName)
+	}
+	if !cfg.IsDirectory {
+		return nil, fmt.Errorf("skill output path must be a directory (end with '/'): %s", cfg.RelPath)
+	}
+
+	strategy := skillStrategy{p: p.BaseProcessor, agentName: cfg.AgentName}
+	result := &TaskResult{Files: []ProcessedFile{}}
+	for _, input := range inputs {
+		absSkillDir := filepath.Dir(input)
+		if !filepath.IsAbs(absSkillDir) {
+			absSkillDir = util.JoinPath(p.absInputRoot, absSkillDir)
+		}
+
+		// Each skill is written to its own directory named after the source directory
+		skillCfg := *cfg
+		skillCfg.RelPath = filepath.Join(cfg.RelPath, filepath.Base(absSkillDir)) + "/"
+
+		skillResult, err := processGeneric(p.BaseProcessor, []string{input}, &skillCfg, strategy)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, skillResult.Files...)
+
+		assets, err := p.processAssets(absSkillDir, &skillCfg)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, assets...)
+	}
+	return result, nil
+}
+
+// processAssets copies the supporting files of a skill directory.
+// Markdown files are rendered through the template engine; other files are copied as-is.
+// Copies keep the permission bits of their source, so scripts stay executable.
+func (p *SkillProcessor) processAssets(absSkillDir string, cfg *OutputConfig) ([]ProcessedFile, error) {
+	relPaths, err := p.fs.GlobWithExcludes([]string{"**"}, absSkillDir)
+	if err != nil {
+		return nil, fmt.Errorf("list skill files in %s: %w", absSkillDir, err)
+	}
+
+	engine := p.templateEngine(cfg)
+	var files []ProcessedFile
+	for _, rel := range relPaths {
+		if rel == model.SkillFileName {
+			continue
+		}
+		absPath := filepath.Join(absSkillDir, rel)
+		raw, err := p.fs.ReadFile(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("read skill file %s: %w", absPath, err)
+		}
+		mode, err := p.fs.FileMode(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("read skill file mode %s: %w", absPath, err)
+		}
+		relPath := filepath.Join(cfg.RelPath, rel)
+		content := string(raw)
+		if strings.EqualFold(filepath.Ext(rel), ".md") {
+			content, err = engine.Execute(absPath, content, templateData(cfg, relPath))
+			if err != nil {
+				return nil, fmt.Errorf("template execute %s: %w", absPath, err)
+			}
+		}
+		files = append(files, ProcessedFile{
+			relPath:   relPath,
+			Content:   content,
+			AgentName: cfg.AgentName,
+			mode:      mode,
+		})
+	}
+	return files, nil
+}
+
+// GetOutputPath returns the appropriate output path for skill tasks
+func (p *SkillProcessor) GetOutputPath(a agent.Agent, outputPath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+	if f, ok := a.(agent.SkillFormatter); ok {
+		return f.SkillPath(p.userScope)
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/util"
+	"go.uber.org/zap"
+)
+
+func TestSkillProcessor_InputPatterns(t *testing.T) {
+	sp := NewSkillProcessor(nil)
+	got := sp.InputPatterns([]string{"skills/*", "skills/review/", "!skills/draft", "other/*/SKILL.md"})
+	want := []string{"skills/*/SKILL.md", "skills/review/SKILL.md", "!skills/draft/SKILL.md", "other/*/SKILL.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InputPatterns() = %v, want %v", got, want)
+	}
+}
+
+// TestSkillProcessor_Process verifies that a skill directory is written to its own
+// directory with a formatted SKILL.md and its supporting files.
+func TestSkillProcessor_Process(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"skills/pdf/SKILL.md":           "---\ndescription: Work with PDF files\nclaude:\n  allowed-tools: Read, Bash\n---\nRun {{ file \"scripts/extract.py\" }}.\n",
+		"skills/pdf/reference.md":       "{{ if isClaude }}Claude reference{{ end }}\n",
+		"skills/pdf/scripts/extract.py": "print(\"{{ not templated }}\")\n",
+	}
+	for rel, content := range files {
+		abs := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", abs, err)
+		}
+	}
+
+	if err := os.Chmod(filepath.Join(dir, "skills/pdf/scripts/extract.py"), 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), dir, agent.NewRegistry(), false)
+	sp := NewSkillProcessor(base)
+	cfg := &OutputConfig{
+		Agent:       &agent.Claude{},
+		IsDirectory: true,
+		AgentName:   "claude",
+		TaskType:    "skill",
+	}
+	cfg.RelPath = sp.GetOutputPath(cfg.Agent, "")
+
+	result, err := sp.Process([]string{"skills/pdf/SKILL.md"}, cfg)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+
+	got := map[string]string{}
+	modes := map[string]os.FileMode{}
+	for _, f := range result.Files {
+		got[f.relPath] = f.Content
+		modes[f.relPath] = f.mode
+	}
+	skillDir := filepath.Join(".claude", "skills", "pdf")
+	wantFiles := map[string]string{
+		filepath.Join(skillDir, "SKILL.md"):              "---\nname: pdf\ndescription: Work with PDF files\nallowed-tools: Read, Bash\n---\n\nRun @scripts/extract.py.\n",
+		filepath.Join(skillDir, "reference.md"):          "Claude reference\n",
+		filepath.Join(skillDir, "scripts", "extract.py"): "print(\"{{ not templated }}\")\n",
+	}
+	if !reflect.DeepEqual(got, wantFiles) {
+		t.Errorf("files = %#v, want %#v", got, wantFiles)
+	}
+	if mode := modes[filepath.Join(skillDir, "scripts", "extract.py")]; mode != 0o755 {
+		t.Errorf("script mode = %v, want %v", mode, os.FileMode(0o755))
+	}
+}
+
+func TestSkillProcessor_Process_Errors(t *testing.T) {
+	base := NewBaseProcessor(&util.RealFileSystem{}, zap.NewNop(), t.TempDir(), agent.NewRegistry(), false)
+	sp := NewSkillProcessor(base)
+
+	_, err := sp.Process([]string{"skills/pdf/SKILL.md"}, &OutputConfig{Agent: &agent.Roo{}, AgentName: "roo", RelPath: "skills/", IsDirectory: true, TaskType: "skill"})
+	if err == nil || !strings.Contains(err.Error(), "does not support skills") {
+		t.Errorf("expected unsupported agent error, got %v", err)
+	}
+
+	_, err = sp.Process([]string{"skills/pdf/SKILL.md"}, &OutputConfig{Agent: &agent.Claude{}, AgentName: "claude", RelPath: "skills.md", TaskType: "skill"})
+	if err == nil || !strings.Contains(err.Error(), "must be a directory") {
+		t.Errorf("expected directory output error, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+// Catalog indexes the commands and modes defined in the configuration by source name
+// (the input file name without its extension), so that templates can reference them.
+type Catalog struct {
+	commands map[string][]model.Command
+	modes    map[string][]model.Mode
+}
+
+// NewCatalog creates an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{
+		commands: make(map[string][]model.Command),
+		modes:    make(map[string][]model.Mode),
+	}
+}
+
+// AddCommand registers a command. A source file used by several tasks is registered once.
+func (c *Catalog) AddCommand(command model.Command) {
+	name := agent.SourceName(command.Path)
+	for _, existing := range c.commands[name] {
+		if existing.Path == command.Path {
+			return
+		}
+	}
+	c.commands[name] = append(c.commands[name], command)
+}
+
+// AddMode registers a mode. A source file used by several tasks is registered once.
+func (c *Catalog) AddMode(mode model.Mode) {
+	name := agent.SourceName(mode.Path)
+	for _, existing := range c.modes[name] {
+		if existing.Path == mode.Path {
+			return
+		}
+	}
+	c.modes[name] = append(c.modes[name], mode)
+}
+
+// Command looks up a command by source name
+func (c *Catalog) Command(name string) (model.Command, error) {
+	var commands []model.Command
+	if c != nil {
+		commands = c.commands[name]
+	}
+	paths := make([]string, len(commands))
+	for i, command := range commands {
+		paths[i] = command.Path
+	}
+	if err := checkCatalogMatches("command", name, paths); err != nil {
+		return model.Command{}, err
+	}
+	return commands[0], nil
+}
+
+// Mode looks up a mode by source name
+func (c *Catalog) Mode(name string) (model.Mode, error) {
+	var modes []model.Mode
+	if c != nil {
+		modes = c.modes[name]
+	}
+	paths := make([]string, len(modes))
+	for i, mode := range modes {
+		paths[i] = mode.Path
+	}
+	if err := checkCatalogMatches("mode", name, paths); err != nil {
+		return model.Mode{}, err
+	}
+	return modes[0], nil
+}
+
+// checkCatalogMatches reports an error unless exactly one source file matched the name
+func checkCatalogMatches(kind, name string, paths []string) error {
+	switch len(paths) {
+	case 0:
+		return fmt.Errorf("%s %q is not defined in the configuration", kind, name)
+	case 1:
+		return nil
+	default:
+		sort.Strings(paths)
+		return fmt.Errorf("%s %q is ambiguous: it is defined by %s", kind, name, strings.Join(paths, ", "))
+	}
+}
@@ -0,0 +1,101 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+	"github.com/uphy/agent-sync/internal/model"
+)
+
+func TestCommandAndModeFuncs(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.AddCommand(model.Command{Path: "/base/commands/deploy.md"})
+	catalog.AddCommand(model.Command{Path: "/base/commands/deploy.md"}) // used by another task
+	catalog.AddCommand(model.Command{Path: "/base/commands/test.md"})
+	catalog.AddCommand(model.Command{Path: "/base/other/test.md"})
+	catalog.AddMode(model.Mode{
+		Path: "/base/modes/reviewer.md",
+		Raw:  map[string]any{"claude": map[string]any{"name": "code-reviewer"}},
+	})
+
+	tests := []struct {
+		name     string
+		agent    string
+		template string
+		want     string
+		wantErr  []string
+	}{
+		{name: "command", agent: "claude", template: `Run {{ command "deploy" }}`, want: "Run /deploy"},
+		{name: "command for codex", agent: "codex", template: `{{ command "deploy" }}`, want: "/prompts:deploy"},
+		{name: "mode", agent: "claude", template: `Ask {{ mode "reviewer" }}`, want: "Ask @agent-code-reviewer"},
+		{name: "missing command", agent: "claude", template: `{{ command "release" }}`, wantErr: []string{`command "release" is not defined`}},
+		{name: "missing mode", agent: "claude", template: `{{ mode "planner" }}`, wantErr: []string{`mode "planner" is not defined`}},
+		{name: "ambiguous command", agent: "claude", template: `{{ command "test" }}`,
+			wantErr: []string{`command "test" is ambiguous`, "/base/commands/test.md", "/base/other/test.md"}},
+		{name: "unsupported by agent", agent: "codex", template: `{{ mode "reviewer" }}`, wantErr: []string{"does not support modes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(NewMockFileResolver("/base"), tt.agent, "/base", agent.NewRegistry())
+			engine.Catalog = catalog
+			got, err := engine.Execute("/base/main.md", tt.template, nil)
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("expected error to contain %q, got %v", want, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandFunc_NoCatalog(t *testing.T) {
+	engine := NewEngine(NewMockFileResolver("/base"), "claude", "/base", agent.NewRegistry())
+	if _, err := engine.Execute("/base/main.md", `{{ command "deploy" }}`, nil); err == nil {
+		t.Fatal("expected an error without a catalog")
+	}
+}
+
+func TestToolFunc(t *testing.T) {
+	tests := []struct {
+		agent   string
+		want    string
+		wantErr string
+	}{
+		{agent: "claude", want: "Use `Read`."},
+		{agent: "copilot", want: "Use #codebase."},
+		{agent: "cursor", wantErr: "cursor agent does not support tool references"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent, func(t *testing.T) {
+			engine := NewEngine(NewMockFileResolver("/base"), tt.agent, "/base", agent.NewRegistry())
+			got, err := engine.Execute("/base/main.md", `Use {{ tool "read" }}.`, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error to contain %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
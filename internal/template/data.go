@@ -0,0 +1,108 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goccy/go-yaml"
+	"github.com/uphy/agent-sync/internal/util"
+)
+
+// parseDataFile parses the content of a YAML, JSON or TOML data file, chosen by the file extension.
+// Syntax errors are reported with the file path and line.
+func parseDataFile(path string, content []byte) (any, error) {
+	var value any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			var yamlErr yaml.Error
+			if errors.As(err, &yamlErr) && yamlErr.GetToken() != nil {
+				return nil, dataParseError(path, yamlErr.GetToken().Position.Line, yamlErr.GetMessage())
+			}
+			return nil, &util.ErrParseFailure{Path: path, Cause: err}
+		}
+	case ".json":
+		if err := json.Unmarshal(content, &value); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return nil, dataParseError(path, lineAt(content, syntaxErr.Offset), syntaxErr.Error())
+			}
+			return nil, &util.ErrParseFailure{Path: path, Cause: err}
+		}
+	case ".toml":
+		var table map[string]any
+		// TOML parse errors already report the line
+		if err := toml.Unmarshal(content, &table); err != nil {
+			return nil, &util.ErrParseFailure{Path: path, Cause: err}
+		}
+		value = table
+	default:
+		return nil, fmt.Errorf("unsupported data file format %q: %s (supported: .yaml, .yml, .json, .toml)", filepath.Ext(path), path)
+	}
+	return value, nil
+}
+
+// dataParseError returns a parse error pointing at a line of a data file
+func dataParseError(path string, line int, msg string) error {
+	return &util.ErrParseFailure{Path: path, Cause: fmt.Errorf("line %d: %s", line, msg)}
+}
+
+// lineAt returns the 1-based line number of the byte offset in content
+func lineAt(content []byte, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// mergeData merges the data of multiple files: maps are merged key by key, with later files
+// taking precedence and nested maps merged recursively, and lists are concatenated.
+func mergeData(base, next any) (any, error) {
+	switch b := base.(type) {
+	case map[string]any:
+		n, ok := next.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot merge %s into a map", dataKind(next))
+		}
+		merged := make(map[string]any, len(b)+len(n))
+		for key, value := range b {
+			merged[key] = value
+		}
+		for key, value := range n {
+			if existing, ok := merged[key].(map[string]any); ok {
+				if nested, ok := value.(map[string]any); ok {
+					value, _ = mergeData(existing, nested)
+				}
+			}
+			merged[key] = value
+		}
+		return merged, nil
+	case []any:
+		n, ok := next.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot merge %s into a list", dataKind(next))
+		}
+		return append(append([]any{}, b...), n...), nil
+	default:
+		return nil, fmt.Errorf("cannot merge %s values; only maps and lists can be merged", dataKind(base))
+	}
+}
+
+// dataKind describes the kind of a parsed data value for error messages
+func dataKind(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "a map"
+	case []any:
+		return "a list"
+	case nil:
+		return "an empty document"
+	default:
+		return fmt.Sprintf("a %T", value)
+	}
+}
@@ -0,0 +1,147 @@
+package template
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+)
+
+// newFileTestEngine returns an engine rendering /base/main.md with the given files
+func newFileTestEngine(files map[string]string) *Engine {
+	resolver := NewMockFileResolver("/base")
+	for path, content := range files {
+		resolver.AddFile(path, content)
+		resolver.ExpectGlob(path, []string{path})
+	}
+	registry := agent.NewRegistry()
+	registry.Register(&agent.Claude{})
+	return NewEngine(resolver, "claude", "/base", registry)
+}
+
+func TestDataFunc(t *testing.T) {
+	files := map[string]string{
+		"/base/data/services.yaml": "services:\n  - name: api\n    port: 8080\n  - name: web\n    port: 3000\n",
+		"/base/data/team.json":     `{"team": {"lead": "alice", "size": 3}}`,
+		"/base/data/limits.toml":   "[limits]\ncpu = 2\nmemory = \"1Gi\"\n",
+		"/base/data/base.yaml":     "team:\n  lead: alice\n  size: 3\n",
+		"/base/data/override.yaml": "team:\n  lead: bob\n",
+		"/base/data/list1.yaml":    "- a\n- b\n",
+		"/base/data/list2.yaml":    "- c\n",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "yaml relative to the current file",
+			template: `{{ range (data "data/services.yaml").services }}{{ .name }}:{{ .port }} {{ end }}`,
+			want:     "api:8080 web:3000 ",
+		},
+		{
+			name:     "json relative to the config directory",
+			template: `{{ $d := data "@/data/team.json" }}{{ $d.team.lead }} {{ $d.team.size }}`,
+			want:     "alice 3",
+		},
+		{
+			name:     "toml",
+			template: `{{ with data "data/limits.toml" }}{{ .limits.cpu }} {{ .limits.memory }}{{ end }}`,
+			want:     "2 1Gi",
+		},
+		{
+			name:     "maps are merged key by key",
+			template: `{{ $d := data "data/base.yaml" "data/override.yaml" }}{{ $d.team.lead }} {{ $d.team.size }}`,
+			want:     "bob 3",
+		},
+		{
+			name:     "lists are concatenated",
+			template: `{{ range data "data/list1.yaml" "data/list2.yaml" }}{{ . }}{{ end }}`,
+			want:     "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newFileTestEngine(files)
+			got, err := engine.Execute("/base/main.md", tt.template, nil)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDataFunc_Errors(t *testing.T) {
+	files := map[string]string{
+		"/base/bad.yaml":  "a: 1\nb: [1, 2\nc: 3\n",
+		"/base/bad.json":  "{\n  \"a\": 1,\n  \"b\": }\n",
+		"/base/bad.toml":  "a = 1\nb = ]\nc = 2\n",
+		"/base/data.txt":  "text",
+		"/base/map.yaml":  "a: 1\n",
+		"/base/list.yaml": "- 1\n",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{name: "yaml syntax", template: `{{ data "bad.yaml" }}`, want: []string{"/base/bad.yaml", "line 3"}},
+		{name: "json syntax", template: `{{ data "bad.json" }}`, want: []string{"/base/bad.json", "line 3"}},
+		{name: "toml syntax", template: `{{ data "bad.toml" }}`, want: []string{"/base/bad.toml", "line 2"}},
+		{name: "unsupported format", template: `{{ data "data.txt" }}`, want: []string{"unsupported data file format"}},
+		{name: "no match", template: `{{ data "missing.yaml" }}`, want: []string{"no data files match"}},
+		{name: "incompatible merge", template: `{{ data "map.yaml" "list.yaml" }}`, want: []string{"/base/map.yaml", "cannot merge a map into a list"}},
+		{name: "absolute path", template: `{{ data "/etc/data.yaml" }}`, want: []string{"absolute paths are not allowed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newFileTestEngine(files)
+			_, err := engine.Execute("/base/main.md", tt.template, nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// failingReadResolver is a file resolver whose reads fail with err
+type failingReadResolver struct {
+	*MockFileResolver
+	err error
+}
+
+func (r failingReadResolver) Read(path string) ([]byte, error) {
+	return nil, r.err
+}
+
+func TestDataFunc_ReadError(t *testing.T) {
+	resolver := NewMockFileResolver("/base")
+	resolver.AddFile("/base/data.yaml", "a: 1\n")
+	resolver.ExpectGlob("/base/data.yaml", []string{"/base/data.yaml"})
+	registry := agent.NewRegistry()
+	registry.Register(&agent.Claude{})
+	engine := NewEngine(failingReadResolver{resolver, fs.ErrPermission}, "claude", "/base", registry)
+
+	_, err := engine.Execute("/base/main.md", `{{ data "data.yaml" }}`, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"read data file /base/data.yaml", fs.ErrPermission.Error()} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %v", want, err)
+		}
+	}
+}
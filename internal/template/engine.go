@@ -46,8 +46,14 @@ type Engine struct {
 	// absCurrentFilePath is the absolute path of the file currently being processed
 	absCurrentFilePath string
 
+	// data is the data of the template currently being processed, passed on to included templates
+	data any
+
 	// AgentRegistry provides access to registered agents
 	AgentRegistry *agent.Registry
+
+	// Catalog holds the commands and modes that can be referenced with the command and mode functions
+	Catalog *Catalog
 }
 
 // Context holds the context information for template processing,
@@ -56,6 +62,39 @@ type Context struct {
 	Agent string
 }
 
+// Data is the data passed to templates of input files
+type Data struct {
+	// Vars are the user-defined variables merged from the root, project, task and output levels
+	Vars map[string]any
+	// Project describes the project of the task; empty for user-level tasks
+	Project ProjectData
+	// Task describes the task being processed
+	Task TaskData
+	// Scope is "project" for project-level tasks and "user" for user-level tasks
+	Scope string
+	// Agent is the identifier of the output agent
+	Agent string
+	// OutputPath is the path of the output file relative to the output directory, using forward slashes
+	OutputPath string
+}
+
+// ProjectData describes the project of the task being processed
+type ProjectData struct {
+	Name string
+}
+
+// TaskData describes the task being processed
+type TaskData struct {
+	Name string
+	Type string
+}
+
+// Template scopes exposed as Data.Scope
+const (
+	ScopeProject = "project"
+	ScopeUser    = "user"
+)
+
 // NewEngine creates a new template engine
 func NewEngine(fileResolver FileResolver, agentType, absTemplateBaseDir string, agentRegistry *agent.Registry) *Engine {
 	return &Engine{
@@ -67,8 +106,15 @@ func NewEngine(fileResolver FileResolver, agentType, absTemplateBaseDir string,
 	}
 }
 
-// Execute processes a template with the given data while managing CurrentFilePath
+// Execute processes a template with the given data while managing CurrentFilePath.
+// Templates included with include or reference receive the same data.
 func (e *Engine) Execute(absFilePath string, content string, data any) (string, error) {
+	previousData := e.data
+	e.data = data
+	defer func() {
+		e.data = previousData
+	}()
+
 	// Process the template content with proper path management
 	result, err := e.executeWithoutReferencesWithPath(absFilePath, content, data)
 	if err != nil {
@@ -156,13 +202,19 @@ func (e *Engine) executeWithoutReferencesWithPath(absFilePath string, content st
 // RegisterHelperFunctions registers all template helper functions
 func (e *Engine) RegisterHelperFunctions(t *template.Template) *template.Template {
 	funcMap := template.FuncMap{
-		"file":         e.FileFunc(),
-		"include":      e.IncludeFunc(true),
-		"reference":    e.ReferenceFunc(true),
-		"includeRaw":   e.IncludeFunc(false),
-		"referenceRaw": e.ReferenceFunc(false),
-		"mcp":          e.MCPFunc(),
-		"agent":        e.Agent,
+		"file":           e.FileFunc(),
+		"include":        e.IncludeFunc(true),
+		"reference":      e.ReferenceFunc(true),
+		"includeRaw":     e.IncludeFunc(false),
+		"referenceRaw":   e.ReferenceFunc(false),
+		"includeSection": e.IncludeSectionFunc(),
+		"includeCode":    e.IncludeCodeFunc(),
+		"data":           e.DataFunc(),
+		"mcp":            e.MCPFunc(),
+		"command":        e.CommandFunc(),
+		"mode":           e.ModeFunc(),
+		"tool":           e.ToolFunc(),
+		"agent":          e.Agent,
 	}
 	caser := cases.Title(language.English)
 	for _, agent := range e.AgentRegistry.List() {
@@ -201,7 +253,7 @@ func (e *Engine) processInclude(path string, processTemplate bool) (string, erro
 
 	if processTemplate {
 		// Process the content as a template recursively using path management
-		processed, err := e.executeWithoutReferencesWithPath(path, string(content), nil)
+		processed, err := e.executeWithoutReferencesWithPath(path, string(content), e.data)
 		if err != nil {
 			return "", &util.ErrTemplateExecution{
 				Template: path,
@@ -297,6 +297,29 @@ func TestRecursiveTemplateProcessing(t *testing.T) {
 	}
 }
 
+func TestIncludePassesData(t *testing.T) {
+	mockResolver := NewMockFileResolver("/base")
+	mockResolver.AddFile("/base/partial.md", "{{ .Vars.repo }} for {{ .Project.Name }}")
+	mockResolver.ExpectGlob("/base/partial.md", []string{"/base/partial.md"})
+
+	registry := agent.NewRegistry()
+	registry.Register(&agent.Claude{})
+
+	engine := NewEngine(mockResolver, "claude", "/base", registry)
+	data := Data{
+		Vars:    map[string]any{"repo": "agent-sync"},
+		Project: ProjectData{Name: "web"},
+		Agent:   "claude",
+	}
+	output, err := engine.Execute("/base/main.md", `{{ include "partial.md" }} ({{ .Agent }})`, data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if output != "agent-sync for web (claude)" {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
 func TestReferenceCollection(t *testing.T) {
 	// Setup
 	mockResolver := NewMockFileResolver("/base")
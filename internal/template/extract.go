@@ -0,0 +1,152 @@
+package template
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// extractSection returns the Markdown section starting at the given heading, up to the next heading
+// of the same or a higher level. A heading with leading '#' characters must match the level and text
+// exactly; a heading without them matches the first heading of any level with that text.
+// Headings inside fenced code blocks are ignored.
+func extractSection(content, heading string) (string, bool) {
+	wantLevel, wantText := parseHeading(heading)
+	if wantLevel == 0 {
+		wantText = strings.TrimSpace(heading)
+	}
+
+	lines := strings.Split(content, "\n")
+	start, level := -1, 0
+	inFence := false
+	for i, line := range lines {
+		if isFenceLine(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		l, text := parseHeading(line)
+		if l == 0 {
+			continue
+		}
+		if start < 0 {
+			if text == wantText && (wantLevel == 0 || l == wantLevel) {
+				start, level = i, l
+			}
+			continue
+		}
+		if l <= level {
+			return joinSection(lines[start:i]), true
+		}
+	}
+	if start < 0 {
+		return "", false
+	}
+	return joinSection(lines[start:]), true
+}
+
+// parseHeading returns the level and text of an ATX heading line, or level 0 if the line is not a heading
+func parseHeading(line string) (int, string) {
+	trimmed := strings.TrimRight(line, " \t\r")
+	if strings.HasPrefix(trimmed, "    ") {
+		return 0, ""
+	}
+	trimmed = strings.TrimLeft(trimmed, " ")
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, ""
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, ""
+	}
+	// Optional closing sequence of '#' characters
+	text := strings.TrimSpace(rest)
+	if closed := strings.TrimRight(text, "#"); closed != text && (closed == "" || strings.HasSuffix(closed, " ")) {
+		text = strings.TrimSpace(closed)
+	}
+	return level, text
+}
+
+// isFenceLine reports whether a line opens or closes a fenced code block
+func isFenceLine(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// joinSection joins the lines of a section, dropping trailing blank lines
+func joinSection(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeLanguages maps file extensions to the language tags of fenced code blocks
+var codeLanguages = map[string]string{
+	".c":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".cs":    "csharp",
+	".css":   "css",
+	".go":    "go",
+	".h":     "c",
+	".hpp":   "cpp",
+	".html":  "html",
+	".java":  "java",
+	".js":    "javascript",
+	".json":  "json",
+	".jsx":   "jsx",
+	".kt":    "kotlin",
+	".md":    "markdown",
+	".php":   "php",
+	".py":    "python",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".sh":    "bash",
+	".sql":   "sql",
+	".swift": "swift",
+	".toml":  "toml",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".xml":   "xml",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+}
+
+// codeLanguage returns the language tag for a fenced code block of the file at path.
+// Unknown extensions are used as the tag as-is.
+func codeLanguage(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if lang, ok := codeLanguages[ext]; ok {
+		return lang
+	}
+	return strings.TrimPrefix(ext, ".")
+}
+
+// extractLines returns lines start to end (1-based, inclusive) of content
+func extractLines(content string, start, end int) (string, error) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if start < 1 || end < start {
+		return "", fmt.Errorf("invalid line range %d-%d", start, end)
+	}
+	if end > len(lines) {
+		return "", fmt.Errorf("line range %d-%d is out of range (the file has %d lines)", start, end, len(lines))
+	}
+	return strings.Join(lines[start-1:end], "\n"), nil
+}
+
+// fenceCode wraps code in a fenced code block with the given language tag,
+// using a fence longer than any backtick run in the code
+func fenceCode(code, lang string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
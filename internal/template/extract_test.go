@@ -0,0 +1,157 @@
+package template
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/uphy/agent-sync/internal/agent"
+)
+
+const sectionDoc = `# Design
+
+Intro.
+
+## Error handling
+
+Errors are wrapped.
+
+` + "```sh\n# not a heading\n```" + `
+
+### Retries
+
+Retry twice.
+
+## Logging
+
+Use zap.
+`
+
+func TestExtractSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		heading string
+		want    string
+		found   bool
+	}{
+		{
+			name:    "section with subsections up to the next heading of the same level",
+			heading: "## Error handling",
+			want:    "## Error handling\n\nErrors are wrapped.\n\n```sh\n# not a heading\n```\n\n### Retries\n\nRetry twice.",
+			found:   true,
+		},
+		{
+			name:    "last section",
+			heading: "## Logging",
+			want:    "## Logging\n\nUse zap.",
+			found:   true,
+		},
+		{
+			name:    "heading text without level",
+			heading: "Retries",
+			want:    "### Retries\n\nRetry twice.",
+			found:   true,
+		},
+		{name: "level must match", heading: "# Retries"},
+		{name: "headings in code blocks are ignored", heading: "# not a heading"},
+		{name: "missing", heading: "## Testing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := extractSection(sectionDoc, tt.heading)
+			if found != tt.found || got != tt.want {
+				t.Errorf("extractSection() = %q, %v; want %q, %v", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestIncludeSectionAndCodeFuncs(t *testing.T) {
+	files := map[string]string{
+		"/base/docs/design.md": sectionDoc,
+		"/base/src/main.go":    "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n",
+		"/base/src/readme.md":  "Use:\n```\nrun\n```\n",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "section",
+			template: `{{ includeSection "@/docs/design.md" "## Logging" }}`,
+			want:     "## Logging\n\nUse zap.",
+		},
+		{
+			name:     "line range",
+			template: `{{ includeCode "src/main.go" 3 5 }}`,
+			want:     "```go\nfunc main() {\n\tprintln(\"hi\")\n}\n```",
+		},
+		{
+			name:     "whole file",
+			template: `{{ includeCode "src/main.go" }}`,
+			want:     "```go\npackage main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n```",
+		},
+		{
+			name:     "fence longer than the code's backticks",
+			template: `{{ includeCode "src/readme.md" }}`,
+			want:     "````markdown\nUse:\n```\nrun\n```\n````",
+		},
+		{
+			name:     "missing section",
+			template: `{{ includeSection "docs/design.md" "## Testing" }}`,
+			wantErr:  `section "## Testing" not found in /base/docs/design.md`,
+		},
+		{
+			name:     "out of range",
+			template: `{{ includeCode "src/main.go" 3 10 }}`,
+			wantErr:  "/base/src/main.go: line range 3-10 is out of range (the file has 5 lines)",
+		},
+		{
+			name:     "invalid range",
+			template: `{{ includeCode "src/main.go" 4 2 }}`,
+			wantErr:  "invalid line range 4-2",
+		},
+		{
+			name:     "missing file",
+			template: `{{ includeCode "src/missing.go" 1 2 }}`,
+			wantErr:  "file not found: src/missing.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newFileTestEngine(files)
+			got, err := engine.Execute("/base/main.md", tt.template, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIncludeCodeFunc_ReadError(t *testing.T) {
+	resolver := NewMockFileResolver("/base")
+	resolver.AddFile("/base/src/main.go", "package main\n")
+	resolver.ExpectGlob("/base/src/main.go", []string{"/base/src/main.go"})
+	registry := agent.NewRegistry()
+	registry.Register(&agent.Claude{})
+	engine := NewEngine(failingReadResolver{resolver, fs.ErrPermission}, "claude", "/base", registry)
+
+	_, err := engine.Execute("/base/main.md", `{{ includeCode "src/main.go" }}`, nil)
+	if err == nil || !strings.Contains(err.Error(), "read /base/src/main.go: "+fs.ErrPermission.Error()) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+}
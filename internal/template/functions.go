@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/uphy/agent-sync/internal/agent"
 	"github.com/uphy/agent-sync/internal/util"
 )
 
@@ -94,6 +95,103 @@ func (e *Engine) ReferenceFunc(processTemplate bool) any {
 	}
 }
 
+// DataFunc generates a helper function that loads structured data from YAML, JSON or TOML files.
+// When the paths match multiple files, their data is merged in order (see mergeData).
+func (e *Engine) DataFunc() any {
+	return func(paths ...string) (any, error) {
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("at least one path must be provided")
+		}
+
+		resolvedPaths, err := e.resolveTemplatePath(paths)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolvedPaths) == 0 {
+			return nil, fmt.Errorf("no data files match %q", paths)
+		}
+
+		var result any
+		for i, fullPath := range resolvedPaths {
+			content, err := e.FileResolver.Read(fullPath)
+			if err != nil {
+				return nil, fmt.Errorf("read data file %s: %w", fullPath, err)
+			}
+			value, err := parseDataFile(fullPath, content)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				result = value
+				continue
+			}
+			if result, err = mergeData(result, value); err != nil {
+				return nil, fmt.Errorf("merge data file %s: %w", fullPath, err)
+			}
+		}
+		return result, nil
+	}
+}
+
+// IncludeSectionFunc generates a helper function that includes one section of a Markdown file,
+// from the given heading up to the next heading of the same or a higher level, without template processing
+func (e *Engine) IncludeSectionFunc() any {
+	return func(path, heading string) (string, error) {
+		fullPath, content, err := e.readSingleFile(path)
+		if err != nil {
+			return "", err
+		}
+		section, found := extractSection(content, heading)
+		if !found {
+			return "", fmt.Errorf("section %q not found in %s", heading, fullPath)
+		}
+		return section, nil
+	}
+}
+
+// IncludeCodeFunc generates a helper function that includes a file, or lines start to end of it
+// (1-based, inclusive), as a fenced code block tagged with the language of the file
+func (e *Engine) IncludeCodeFunc() any {
+	return func(path string, lineRange ...int) (string, error) {
+		fullPath, content, err := e.readSingleFile(path)
+		if err != nil {
+			return "", err
+		}
+		code := strings.TrimSuffix(content, "\n")
+		switch len(lineRange) {
+		case 0:
+		case 2:
+			code, err = extractLines(content, lineRange[0], lineRange[1])
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", fullPath, err)
+			}
+		default:
+			return "", fmt.Errorf("includeCode expects a path and optionally a start and end line, got %d line arguments", len(lineRange))
+		}
+		return fenceCode(code, codeLanguage(fullPath)), nil
+	}
+}
+
+// readSingleFile resolves a template path that must match exactly one file and reads it
+func (e *Engine) readSingleFile(path string) (string, string, error) {
+	resolvedPaths, err := e.resolveTemplatePath([]string{path})
+	if err != nil {
+		return "", "", err
+	}
+	switch len(resolvedPaths) {
+	case 0:
+		return "", "", &util.ErrFileNotFound{Path: path}
+	case 1:
+	default:
+		return "", "", fmt.Errorf("path %q matches %d files; exactly one is required", path, len(resolvedPaths))
+	}
+	content, err := e.FileResolver.Read(resolvedPaths[0])
+	if err != nil {
+		return "", "", fmt.Errorf("read %s: %w", resolvedPaths[0], err)
+	}
+	return resolvedPaths[0], string(content), nil
+}
+
 // MCPFunc generates an MCP command helper function
 func (e *Engine) MCPFunc() any {
 	return func(agentName, command string, args ...string) (string, error) {
@@ -108,6 +206,51 @@ func (e *Engine) MCPFunc() any {
 	}
 }
 
+// CommandFunc generates a function rendering how the current agent invokes a command of the configuration
+func (e *Engine) CommandFunc() any {
+	return func(name string) (string, error) {
+		command, err := e.Catalog.Command(name)
+		if err != nil {
+			return "", err
+		}
+		agent, found := e.AgentRegistry.Get(e.AgentType)
+		if !found {
+			return "", &util.ErrInvalidAgent{Type: e.AgentType}
+		}
+		return agent.FormatCommandRef(command)
+	}
+}
+
+// ModeFunc generates a function rendering how the current agent references a mode of the configuration
+func (e *Engine) ModeFunc() any {
+	return func(name string) (string, error) {
+		mode, err := e.Catalog.Mode(name)
+		if err != nil {
+			return "", err
+		}
+		agent, found := e.AgentRegistry.Get(e.AgentType)
+		if !found {
+			return "", &util.ErrInvalidAgent{Type: e.AgentType}
+		}
+		return agent.FormatModeRef(mode)
+	}
+}
+
+// ToolFunc generates a function rendering the current agent's tools for a neutral tool name
+func (e *Engine) ToolFunc() any {
+	return func(name string) (string, error) {
+		a, found := e.AgentRegistry.Get(e.AgentType)
+		if !found {
+			return "", &util.ErrInvalidAgent{Type: e.AgentType}
+		}
+		formatter, ok := a.(agent.ToolFormatter)
+		if !ok {
+			return "", fmt.Errorf("%s agent does not support tool references", e.AgentType)
+		}
+		return formatter.FormatToolRef(name)
+	}
+}
+
 // Agent returns the current agent type being used for template processing.
 func (e *Engine) Agent() string {
 	return e.AgentType
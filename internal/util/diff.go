@@ -0,0 +1,187 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// maxDiffCells bounds the size of the table used to diff the changed region of two texts.
+// Larger regions are shown as a whole replacement instead of a minimal diff.
+const maxDiffCells = 4 << 20
+
+// noNewlineMarker is appended to a last line without a trailing newline, so that it differs
+// from the same line with a newline and is written with a "\ No newline at end of file" note
+const noNewlineMarker = "\x00"
+
+// diffOp is a single line of a line-based diff
+type diffOp struct {
+	kind byte // ' ' (equal), '-' (delete) or '+' (insert)
+	line string
+}
+
+// UnifiedDiff returns a unified diff between two texts, labelled with oldName and newName.
+// It returns an empty string when the texts are equal.
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Group changes into hunks, merging changes separated by at most twice the context
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+		first := max(start-diffContextLines, 0)
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i
+			} else if i-end > 2*diffContextLines {
+				break
+			}
+		}
+		last := min(end+diffContextLines, len(ops)-1)
+		writeHunk(&b, ops, first, last)
+		start = last + 1
+	}
+	return b.String()
+}
+
+// writeHunk writes the hunk of ops[first:last+1] with its header
+func writeHunk(b *strings.Builder, ops []diffOp, first, last int) {
+	// Line numbers of the hunk start in the old and new texts (1-based)
+	oldStart, newStart := 1, 1
+	for _, op := range ops[:first] {
+		if op.kind != '+' {
+			oldStart++
+		}
+		if op.kind != '-' {
+			newStart++
+		}
+	}
+	oldLines, newLines := 0, 0
+	for _, op := range ops[first : last+1] {
+		if op.kind != '+' {
+			oldLines++
+		}
+		if op.kind != '-' {
+			newLines++
+		}
+	}
+	// An empty range starts at the line before it, as in diff -u
+	if oldLines == 0 {
+		oldStart--
+	}
+	if newLines == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLines, newStart, newLines)
+	for _, op := range ops[first : last+1] {
+		line, noNewline := strings.CutSuffix(op.line, noNewlineMarker)
+		b.WriteByte(op.kind)
+		b.WriteString(line)
+		b.WriteByte('\n')
+		if noNewline {
+			b.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
+
+// diffLines computes a line diff. Common leading and trailing lines are matched first,
+// and the lines in between are diffed based on their longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, diffLCS(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// diffLCS computes a line diff based on the longest common subsequence.
+// When the table would exceed maxDiffCells, all lines of a are replaced by all lines of b.
+func diffLCS(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines splits text into lines, ignoring the final newline.
+// A last line without a newline is marked with noNewlineMarker.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	trimmed, hasNewline := strings.CutSuffix(text, "\n")
+	lines := strings.Split(trimmed, "\n")
+	if !hasNewline {
+		lines[len(lines)-1] += noNewlineMarker
+	}
+	return lines
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "separate hunks",
+			old:  "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n",
+			new:  "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			want: "--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+				"@@ -11,3 +11,4 @@\n k\n l\n m\n+n\n",
+		},
+		{
+			name: "nearby changes share a hunk",
+			old:  "a\nb\nc\nd\ne\nf\n",
+			new:  "A\nb\nc\nd\ne\nF\n",
+			want: "--- old\n+++ new\n@@ -1,6 +1,6 @@\n-a\n+A\n b\n c\n d\n e\n-f\n+F\n",
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  "x\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+x\n",
+		},
+		{
+			name: "missing final newline",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", tt.old, tt.new); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff_Large(t *testing.T) {
+	// Changed regions too large to diff line by line are shown as a whole replacement
+	var old, new strings.Builder
+	old.WriteString("head\n")
+	new.WriteString("head\n")
+	for i := 0; i < 3000; i++ {
+		old.WriteString("old\n")
+		new.WriteString("new\n")
+	}
+	old.WriteString("tail\n")
+	new.WriteString("tail\n")
+
+	got := UnifiedDiff("old", "new", old.String(), new.String())
+	want := "--- old\n+++ new\n@@ -1,3002 +1,3002 @@\n head\n" +
+		strings.Repeat("-old\n", 3000) + strings.Repeat("+new\n", 3000) + " tail\n"
+	if got != want {
+		t.Errorf("UnifiedDiff() = %.200q..., want %.200q...", got, want)
+	}
+}
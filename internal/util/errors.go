@@ -1,6 +1,39 @@
 package util
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Exit statuses of the agent-sync command
+const (
+	// ExitCodeError is reported for any failure without a more specific exit status
+	ExitCodeError = 1
+	// ExitCodeInvalidConfig is reported when the configuration file is invalid
+	ExitCodeInvalidConfig = 2
+	// ExitCodeOutOfDate is reported when generated files differ from the files on disk
+	ExitCodeOutOfDate = 3
+)
+
+// ExitCoder is implemented by errors that are reported with a specific exit status
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// ExitCodeOf returns the exit status for err: 0 for nil, the exit status of the first
+// ExitCoder in the error chain, or ExitCodeError otherwise
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitCodeError
+}
 
 // CustomError defines the interface for custom error types
 // that can provide formatted error messages
@@ -110,6 +143,54 @@ func (e *ErrInvalidConfig) FormattedError() string {
 	return fmt.Sprintf("Configuration error: %s", e.Reason)
 }
 
+// ExitCode returns the exit status reported for invalid configurations
+func (e *ErrInvalidConfig) ExitCode() int {
+	return ExitCodeInvalidConfig
+}
+
+// ErrOutputsOutOfDate reports that the generated files on disk differ from what the configuration generates
+type ErrOutputsOutOfDate struct {
+	// Stale lists files whose content differs from the generated content
+	Stale []string
+	// Missing lists generated files that do not exist
+	Missing []string
+	// Orphaned lists files generated by a previous run that are no longer generated
+	Orphaned []string
+}
+
+func (e *ErrOutputsOutOfDate) Error() string {
+	return fmt.Sprintf("generated files are out of date: %d stale, %d missing, %d orphaned",
+		len(e.Stale), len(e.Missing), len(e.Orphaned))
+}
+
+// FormattedError returns a user-friendly error message listing the out-of-date files
+func (e *ErrOutputsOutOfDate) FormattedError() string {
+	var b strings.Builder
+	b.WriteString("Generated files are out of date. Run 'agent-sync apply' to update them.")
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{"Stale", e.Stale},
+		{"Missing", e.Missing},
+		{"Orphaned", e.Orphaned},
+	} {
+		if len(group.paths) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:", group.label)
+		for _, path := range group.paths {
+			fmt.Fprintf(&b, "\n  %s", path)
+		}
+	}
+	return b.String()
+}
+
+// ExitCode returns the exit status reported when generated files are out of date
+func (e *ErrOutputsOutOfDate) ExitCode() int {
+	return ExitCodeOutOfDate
+}
+
 // WrapError wraps an error with additional context
 func WrapError(err error, message string) error {
 	return fmt.Errorf("%s: %w", message, err)
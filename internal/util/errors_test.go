@@ -0,0 +1,41 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: 0},
+		{name: "plain error", err: errors.New("boom"), want: ExitCodeError},
+		{name: "invalid config", err: &ErrInvalidConfig{Reason: "bad"}, want: ExitCodeInvalidConfig},
+		{name: "wrapped out of date", err: fmt.Errorf("check: %w", &ErrOutputsOutOfDate{}), want: ExitCodeOutOfDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCodeOf(tt.err); got != tt.want {
+				t.Errorf("ExitCodeOf() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrOutputsOutOfDateFormattedError(t *testing.T) {
+	err := &ErrOutputsOutOfDate{
+		Stale:    []string{"/out/a.md"},
+		Orphaned: []string{"/out/b.md", "/out/c.md"},
+	}
+	want := "Generated files are out of date. Run 'agent-sync apply' to update them.\n" +
+		"Stale:\n  /out/a.md\n" +
+		"Orphaned:\n  /out/b.md\n  /out/c.md"
+	if got := err.FormattedError(); got != want {
+		t.Errorf("FormattedError() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -13,6 +13,18 @@ type FileSystem interface {
 	// WriteFile writes content to a file
 	WriteFile(path string, data []byte) error
 
+	// WriteFileMode writes content to a file and sets its permission bits
+	WriteFileMode(path string, data []byte, perm os.FileMode) error
+
+	// FileMode returns the permission bits of a file
+	FileMode(path string) (os.FileMode, error)
+
+	// RemoveFile deletes a file
+	RemoveFile(path string) error
+
+	// RemoveEmptyDir deletes a directory if it is empty and reports whether it was deleted
+	RemoveEmptyDir(path string) (bool, error)
+
 	// FileExists checks if a file exists
 	FileExists(path string) bool
 
@@ -62,6 +74,59 @@ func (fs *RealFileSystem) WriteFile(path string, data []byte) error {
 	return nil
 }
 
+// WriteFileMode writes content to a file and sets its permission bits
+func (fs *RealFileSystem) WriteFileMode(path string, data []byte, perm os.FileMode) error {
+	if err := fs.WriteFile(path, data); err != nil {
+		return err
+	}
+	// os.WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, perm); err != nil {
+		return WrapError(err, "failed to set file mode")
+	}
+	return nil
+}
+
+// FileMode returns the permission bits of a file
+func (fs *RealFileSystem) FileMode(path string) (os.FileMode, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, &ErrFileNotFound{Path: path}
+		}
+		return 0, WrapError(err, "failed to stat file")
+	}
+	return info.Mode().Perm(), nil
+}
+
+// RemoveFile deletes a file
+func (fs *RealFileSystem) RemoveFile(path string) error {
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return &ErrFileNotFound{Path: path}
+		}
+		return WrapError(err, "failed to remove file")
+	}
+	return nil
+}
+
+// RemoveEmptyDir deletes a directory if it is empty and reports whether it was deleted
+func (fs *RealFileSystem) RemoveEmptyDir(path string) (bool, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, WrapError(err, "failed to read directory")
+	}
+	if len(entries) > 0 {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil {
+		return false, WrapError(err, "failed to remove directory")
+	}
+	return true, nil
+}
+
 // FileExists checks if a file exists
 func (fs *RealFileSystem) FileExists(path string) bool {
 	_, err := os.Stat(path)